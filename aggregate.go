@@ -0,0 +1,70 @@
+package box
+
+import "cmp"
+
+// Numeric is a constraint that permits any integer or floating-point type.
+type Numeric interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr |
+		~float32 | ~float64
+}
+
+// SumSome returns the sum of the [Some] values of the slice. [None] values are ignored.
+// Returns zero for an empty or all-[None] slice.
+func SumSome[T Numeric](opts []Optional[T]) T {
+	var sum T
+	for _, opt := range opts {
+		if opt.some {
+			sum += opt.v
+		}
+	}
+
+	return sum
+}
+
+// AvgSome returns the arithmetic mean of the [Some] values of the slice. [None] values are ignored.
+// Returns [None] for an empty or all-[None] slice.
+func AvgSome[T Numeric](opts []Optional[T]) Optional[float64] {
+	var (
+		sum float64
+		n   int
+	)
+	for _, opt := range opts {
+		if opt.some {
+			sum += float64(opt.v)
+			n++
+		}
+	}
+
+	if n == 0 {
+		return None[float64]()
+	}
+
+	return Some(sum / float64(n))
+}
+
+// MaxSome returns the greatest of the [Some] values of the slice.
+// Returns [None] for an empty or all-[None] slice.
+func MaxSome[T cmp.Ordered](opts []Optional[T]) Optional[T] {
+	var res Optional[T]
+	for _, opt := range opts {
+		if opt.some && (!res.some || cmp.Less(res.v, opt.v)) {
+			res = opt
+		}
+	}
+
+	return res
+}
+
+// MinSome returns the least of the [Some] values of the slice.
+// Returns [None] for an empty or all-[None] slice.
+func MinSome[T cmp.Ordered](opts []Optional[T]) Optional[T] {
+	var res Optional[T]
+	for _, opt := range opts {
+		if opt.some && (!res.some || cmp.Less(opt.v, res.v)) {
+			res = opt
+		}
+	}
+
+	return res
+}
@@ -0,0 +1,61 @@
+package box
+
+import "testing"
+
+func TestSumSome(t *testing.T) {
+	tests := []struct {
+		name string
+		in   []Optional[int]
+		want int
+	}{
+		{"mixed", []Optional[int]{Some(1), None[int](), Some(2), Some(3)}, 6},
+		{"all none", []Optional[int]{None[int](), None[int]()}, 0},
+		{"empty", nil, 0},
+	}
+
+	for _, tt := range tests {
+		if got := SumSome(tt.in); got != tt.want {
+			t.Errorf("%s: SumSome() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestAvgSome(t *testing.T) {
+	tests := []struct {
+		name string
+		in   []Optional[float32]
+		want Optional[float64]
+	}{
+		{"mixed", []Optional[float32]{Some[float32](1), None[float32](), Some[float32](2)}, Some(1.5)},
+		{"all none", []Optional[float32]{None[float32](), None[float32]()}, None[float64]()},
+		{"empty", nil, None[float64]()},
+	}
+
+	for _, tt := range tests {
+		if got := AvgSome(tt.in); got != tt.want {
+			t.Errorf("%s: AvgSome() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestMaxMinSome(t *testing.T) {
+	tests := []struct {
+		name     string
+		in       []Optional[int]
+		max, min Optional[int]
+	}{
+		{"mixed", []Optional[int]{Some(2), None[int](), Some(5), Some(-1)}, Some(5), Some(-1)},
+		{"single", []Optional[int]{None[int](), Some(3), None[int]()}, Some(3), Some(3)},
+		{"all none", []Optional[int]{None[int](), None[int]()}, None[int](), None[int]()},
+		{"empty", nil, None[int](), None[int]()},
+	}
+
+	for _, tt := range tests {
+		if got := MaxSome(tt.in); got != tt.max {
+			t.Errorf("%s: MaxSome() = %v, want %v", tt.name, got, tt.max)
+		}
+		if got := MinSome(tt.in); got != tt.min {
+			t.Errorf("%s: MinSome() = %v, want %v", tt.name, got, tt.min)
+		}
+	}
+}
@@ -0,0 +1,131 @@
+package box
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+type benchUser struct {
+	ID    int64            `json:"id"`
+	Name  string           `json:"name"`
+	Email Optional[string] `json:"email"`
+}
+
+// benchCases are the values used by benchmarks and the allocation guard.
+// maxMarshalAllocs and maxUnmarshalAllocs are the measured allocations per operation
+// with a small headroom; raise them only together with a justification in the commit message.
+var benchCases = []struct {
+	name               string
+	value              any
+	newDst             func() any
+	maxMarshalAllocs   float64
+	maxUnmarshalAllocs float64
+}{
+	{
+		name:               "int",
+		value:              Some(42),
+		newDst:             func() any { return new(Optional[int]) },
+		maxMarshalAllocs:   6,
+		maxUnmarshalAllocs: 3,
+	},
+	{
+		name:               "string",
+		value:              Some("hello, world"),
+		newDst:             func() any { return new(Optional[string]) },
+		maxMarshalAllocs:   7,
+		maxUnmarshalAllocs: 3,
+	},
+	{
+		name:               "struct",
+		value:              Some(benchUser{ID: 1, Name: "John", Email: Some("john@example.com")}),
+		newDst:             func() any { return new(Optional[benchUser]) },
+		maxMarshalAllocs:   10,
+		maxUnmarshalAllocs: 3,
+	},
+	{
+		name:               "slice",
+		value:              []Optional[int]{Some(1), None[int](), Some(3), None[int](), Some(5), Some(6), None[int](), Some(8)},
+		newDst:             func() any { return new([]Optional[int]) },
+		maxMarshalAllocs:   14,
+		maxUnmarshalAllocs: 8,
+	},
+}
+
+func BenchmarkMarshalJSON(b *testing.B) {
+	for _, bc := range benchCases {
+		b.Run(bc.name, func(b *testing.B) {
+			b.ReportAllocs()
+			for b.Loop() {
+				if _, err := json.Marshal(bc.value); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkUnmarshalJSON(b *testing.B) {
+	for _, bc := range benchCases {
+		b.Run(bc.name, func(b *testing.B) {
+			data, err := json.Marshal(bc.value)
+			if err != nil {
+				b.Fatal(err)
+			}
+
+			b.ReportAllocs()
+			for b.Loop() {
+				if err := json.Unmarshal(data, bc.newDst()); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func TestJSONAllocs(t *testing.T) {
+	if testing.Short() || raceEnabled {
+		t.Skip("skipping allocation guard in short mode or with race detector")
+	}
+
+	for _, bc := range benchCases {
+		data, err := json.Marshal(bc.value)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		n := testing.AllocsPerRun(100, func() {
+			_, _ = json.Marshal(bc.value)
+		})
+		if n > bc.maxMarshalAllocs {
+			t.Errorf("%s: marshal allocs = %v, want <= %v", bc.name, n, bc.maxMarshalAllocs)
+		}
+
+		n = testing.AllocsPerRun(100, func() {
+			_ = json.Unmarshal(data, bc.newDst())
+		})
+		if n > bc.maxUnmarshalAllocs {
+			t.Errorf("%s: unmarshal allocs = %v, want <= %v", bc.name, n, bc.maxUnmarshalAllocs)
+		}
+	}
+}
+
+// BenchmarkOptional_MarshalJSON_registry measures the marshaler registry lookup
+// on the direct MarshalJSON path, with and without registered functions.
+func BenchmarkOptional_MarshalJSON_registry(b *testing.B) {
+	opt := Some(42)
+	run := func(b *testing.B) {
+		b.ReportAllocs()
+		for b.Loop() {
+			if _, err := opt.MarshalJSON(); err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+
+	b.Run("empty", run)
+	b.Run("other type registered", func(b *testing.B) {
+		RegisterMarshaler(func(m testMoney) ([]byte, error) { return nil, nil })
+		b.Cleanup(UnregisterMarshaler[testMoney])
+		run(b)
+	})
+}
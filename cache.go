@@ -0,0 +1,62 @@
+package box
+
+import (
+	"errors"
+	"sync"
+)
+
+// LoadingOptional caches optional values loaded by key.
+// Concurrent loads of the same key are deduplicated: only one call of load runs,
+// other callers wait for its result. Both [Some] and [None] results are cached,
+// errors are not, so the next call retries the load.
+//
+// Zero value of LoadingOptional is an empty cache ready to use.
+// LoadingOptional is safe for concurrent use and must not be copied after first use.
+type LoadingOptional[K comparable, V any] struct {
+	mu      sync.Mutex
+	entries map[K]*loadEntry[V]
+}
+
+type loadEntry[V any] struct {
+	done chan struct{}
+	val  Optional[V]
+	err  error
+}
+
+// Get returns the cached value for the key, calling load if the key is not cached yet.
+func (c *LoadingOptional[K, V]) Get(key K, load func(K) (Optional[V], error)) (Optional[V], error) {
+	c.mu.Lock()
+	if e, ok := c.entries[key]; ok {
+		c.mu.Unlock()
+		<-e.done
+		return e.val, e.err
+	}
+
+	e := &loadEntry[V]{done: make(chan struct{})}
+	if c.entries == nil {
+		c.entries = make(map[K]*loadEntry[V])
+	}
+	c.entries[key] = e
+	c.mu.Unlock()
+
+	loaded := false
+	defer func() {
+		if !loaded || e.err != nil {
+			if !loaded {
+				e.err = errLoadPanicked
+			}
+			c.mu.Lock()
+			delete(c.entries, key)
+			c.mu.Unlock()
+		}
+		close(e.done)
+	}()
+
+	e.val, e.err = load(key)
+	loaded = true
+
+	return e.val, e.err
+}
+
+// errLoadPanicked is returned to the callers waiting for a load that panicked.
+var errLoadPanicked = errors.New("box: load function panicked")
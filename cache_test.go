@@ -0,0 +1,104 @@
+package box
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+func TestLoadingOptional_Get(t *testing.T) {
+	var (
+		c     LoadingOptional[int, string]
+		calls [2]atomic.Int32
+		start = make(chan struct{})
+	)
+
+	load := func(key int) (Optional[string], error) {
+		calls[key].Add(1)
+		<-start
+		if key == 0 {
+			return None[string](), nil
+		}
+		return Some("user"), nil
+	}
+
+	var wg sync.WaitGroup
+	results := make([]Optional[string], 20)
+	for i := range results {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			opt, err := c.Get(i%2, load)
+			if err != nil {
+				t.Error(err)
+			}
+			results[i] = opt
+		}()
+	}
+	close(start)
+	wg.Wait()
+
+	for i, opt := range results {
+		want := Some("user")
+		if i%2 == 0 {
+			want = None[string]()
+		}
+		if opt != want {
+			t.Errorf("result %d: got %v, want %v", i, opt, want)
+		}
+	}
+
+	for key := range calls {
+		if _, err := c.Get(key, load); err != nil {
+			t.Fatal(err)
+		}
+		if n := calls[key].Load(); n != 1 {
+			t.Errorf("key %d: load called %d times, want 1", key, n)
+		}
+	}
+}
+
+func TestLoadingOptional_Get_error(t *testing.T) {
+	var (
+		c     LoadingOptional[string, int]
+		calls int
+	)
+	errLoad := errors.New("load failed")
+
+	load := func(string) (Optional[int], error) {
+		calls++
+		if calls == 1 {
+			return None[int](), errLoad
+		}
+		return Some(1), nil
+	}
+
+	if _, err := c.Get("a", load); !errors.Is(err, errLoad) {
+		t.Errorf("got %v, want %v", err, errLoad)
+	}
+	if opt, err := c.Get("a", load); err != nil || opt != Some(1) {
+		t.Errorf("retry: got %v, %v, want Some(1)", opt, err)
+	}
+	if calls != 2 {
+		t.Errorf("load called %d times, want 2", calls)
+	}
+}
+
+func TestLoadingOptional_Get_panic(t *testing.T) {
+	var c LoadingOptional[string, int]
+
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Error("expected panic")
+			}
+		}()
+		_, _ = c.Get("a", func(string) (Optional[int], error) { panic("boom") })
+	}()
+
+	opt, err := c.Get("a", func(string) (Optional[int], error) { return Some(1), nil })
+	if err != nil || opt != Some(1) {
+		t.Errorf("after panic: got %v, %v, want Some(1)", opt, err)
+	}
+}
@@ -0,0 +1,61 @@
+/*
+Package cborbox provides CBOR encoding of box types via [github.com/fxamacker/cbor/v2].
+
+Methods can't be added to [box.Optional] outside of the box package, so cborbox provides
+the wrapper type [Optional] implementing [cbor.Marshaler] and [cbor.Unmarshaler].
+[box.None] is presented in CBOR as null.
+*/
+package cborbox
+
+import (
+	"bytes"
+
+	"github.com/fxamacker/cbor/v2"
+
+	"github.com/sevlyar/box"
+)
+
+var (
+	cborNull      = []byte{0xf6}
+	cborUndefined = []byte{0xf7}
+)
+
+// Optional wraps [box.Optional] to implement CBOR (un)marshalling.
+type Optional[T any] struct {
+	box.Optional[T]
+}
+
+// Wrap returns the CBOR wrapper for the given [box.Optional].
+func Wrap[T any](opt box.Optional[T]) Optional[T] {
+	return Optional[T]{opt}
+}
+
+var (
+	_ cbor.Marshaler   = Optional[any]{}
+	_ cbor.Unmarshaler = (*Optional[any])(nil)
+)
+
+func (opt Optional[T]) MarshalCBOR() ([]byte, error) {
+	if opt.IsNone() {
+		return cborNull, nil
+	}
+
+	return cbor.Marshal(opt.Get())
+}
+
+// UnmarshalCBOR decodes CBOR null and undefined as [box.None].
+func (opt *Optional[T]) UnmarshalCBOR(data []byte) error {
+	if bytes.Equal(data, cborNull) || bytes.Equal(data, cborUndefined) {
+		opt.Optional = box.None[T]()
+		return nil
+	}
+
+	var v T
+	if err := cbor.Unmarshal(data, &v); err != nil {
+		opt.Optional = box.None[T]()
+		return err
+	}
+
+	opt.Optional = box.Some(v)
+	return nil
+}
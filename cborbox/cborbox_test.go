@@ -0,0 +1,54 @@
+package cborbox
+
+import (
+	"testing"
+
+	"github.com/fxamacker/cbor/v2"
+
+	"github.com/sevlyar/box"
+)
+
+func TestOptional_roundTrip(t *testing.T) {
+	type Reading struct {
+		Sensor string
+		Temp   Optional[float64]
+		Labels Optional[[]string]
+	}
+
+	tests := []Reading{
+		{Sensor: "a", Temp: Wrap(box.Some(21.5)), Labels: Wrap(box.Some([]string{"x"}))},
+		{Sensor: "b"},
+	}
+
+	for _, in := range tests {
+		data, err := cbor.Marshal(in)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		out := Reading{
+			Temp:   Wrap(box.Some(-1.0)),
+			Labels: Wrap(box.Some([]string{"old"})),
+		}
+		if err := cbor.Unmarshal(data, &out); err != nil {
+			t.Fatal(err)
+		}
+
+		if out.Sensor != in.Sensor || out.Temp != in.Temp || out.Labels.IsSome() != in.Labels.IsSome() {
+			t.Errorf("got %+v, want %+v", out, in)
+		}
+		if in.Labels.IsSome() && out.Labels.Get()[0] != in.Labels.Get()[0] {
+			t.Errorf("got labels %v, want %v", out.Labels.Get(), in.Labels.Get())
+		}
+	}
+}
+
+func TestOptional_MarshalCBOR_none(t *testing.T) {
+	data, err := cbor.Marshal(Optional[int]{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(data) != 1 || data[0] != 0xf6 {
+		t.Errorf("got %x, want f6 (null)", data)
+	}
+}
@@ -0,0 +1,12 @@
+module github.com/sevlyar/box/cborbox
+
+go 1.25
+
+require (
+	github.com/fxamacker/cbor/v2 v2.9.4
+	github.com/sevlyar/box v0.0.0
+)
+
+require github.com/x448/float16 v0.8.4 // indirect
+
+replace github.com/sevlyar/box => ../
@@ -0,0 +1,15 @@
+package box
+
+import "context"
+
+// ToContext returns a copy of ctx carrying the [Optional] under the given key.
+func ToContext[T any](ctx context.Context, key any, opt Optional[T]) context.Context {
+	return context.WithValue(ctx, key, opt)
+}
+
+// FromContext returns the [Optional] stored in ctx under the given key by [ToContext].
+// Returns [None] if the key is absent or holds a value of another type.
+func FromContext[T any](ctx context.Context, key any) Optional[T] {
+	opt, _ := ctx.Value(key).(Optional[T])
+	return opt
+}
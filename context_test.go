@@ -0,0 +1,26 @@
+package box
+
+import (
+	"context"
+	"testing"
+)
+
+type testCtxKey string
+
+func TestContext(t *testing.T) {
+	ctx := ToContext(context.Background(), testCtxKey("user"), Some("john"))
+	ctx = context.WithValue(ctx, testCtxKey("plain"), "value")
+
+	if got := FromContext[string](ctx, testCtxKey("user")); got != Some("john") {
+		t.Errorf("present: got %v, want Some(john)", got)
+	}
+	if got := FromContext[string](ctx, testCtxKey("missing")); got != None[string]() {
+		t.Errorf("absent: got %v, want None", got)
+	}
+	if got := FromContext[int](ctx, testCtxKey("user")); got != None[int]() {
+		t.Errorf("type mismatch: got %v, want None", got)
+	}
+	if got := FromContext[string](ctx, testCtxKey("plain")); got != None[string]() {
+		t.Errorf("plain value: got %v, want None", got)
+	}
+}
@@ -0,0 +1,37 @@
+/*
+Package decimalbox provides helpers for box types holding [decimal.Decimal]
+of [github.com/shopspring/decimal], e.g. optional monetary columns.
+
+[decimal.Decimal] implements [sql.Scanner] and [driver.Valuer], so box.Optional[decimal.Decimal]
+scans NUMERIC columns returned by drivers as string, []byte, float64 or int64 values,
+and NULL as [box.None]. [box.Some] values are written to the database as strings.
+
+[decimal.Decimal] values are not comparable with ==, use [Equal] instead.
+*/
+package decimalbox
+
+import (
+	"database/sql"
+	"database/sql/driver"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/sevlyar/box"
+)
+
+var (
+	_ sql.Scanner   = (*decimal.Decimal)(nil)
+	_ driver.Valuer = decimal.Decimal{}
+)
+
+// Equal reports whether both [box.Optional] values are [box.None], or both are [box.Some]
+// and represent the same number. Unlike ==, it ignores the representation, e.g. 1.50 equals 1.5.
+func Equal(a, b box.Optional[decimal.Decimal]) bool {
+	av, aok := a.Take()
+	bv, bok := b.Take()
+	if aok != bok {
+		return false
+	}
+
+	return !aok || av.Equal(bv)
+}
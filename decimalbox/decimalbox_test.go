@@ -0,0 +1,63 @@
+package decimalbox
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/sevlyar/box"
+)
+
+func TestOptional_Scan(t *testing.T) {
+	want := box.Some(decimal.RequireFromString("12.34"))
+
+	for _, src := range []any{"12.34", []byte("12.34"), 12.34} {
+		var opt box.Optional[decimal.Decimal]
+		if err := opt.Scan(src); err != nil {
+			t.Fatalf("%T: %v", src, err)
+		}
+		if !Equal(opt, want) {
+			t.Errorf("%T: got %v, want %v", src, opt, want)
+		}
+
+		v, err := opt.Value()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if v != "12.34" {
+			t.Errorf("%T: Value = %v, want 12.34", src, v)
+		}
+	}
+
+	opt := want
+	if err := opt.Scan(nil); err != nil {
+		t.Fatal(err)
+	}
+	if opt.IsSome() {
+		t.Errorf("NULL: got %v, want None", opt)
+	}
+	if v, err := opt.Value(); err != nil || v != nil {
+		t.Errorf("NULL: Value = %v, %v, want nil", v, err)
+	}
+
+	if err := opt.Scan("abc"); err == nil {
+		t.Error("expected error for invalid decimal")
+	}
+}
+
+func TestEqual(t *testing.T) {
+	none := box.None[decimal.Decimal]()
+	a := box.Some(decimal.RequireFromString("1.50"))
+	b := box.Some(decimal.RequireFromString("1.5"))
+	c := box.Some(decimal.RequireFromString("2"))
+
+	if !Equal(a, b) {
+		t.Error("1.50 and 1.5 are not equal")
+	}
+	if Equal(a, c) || Equal(a, none) || Equal(none, a) {
+		t.Error("different values are equal")
+	}
+	if !Equal(none, none) {
+		t.Error("None values are not equal")
+	}
+}
@@ -0,0 +1,10 @@
+module github.com/sevlyar/box/decimalbox
+
+go 1.25
+
+require (
+	github.com/sevlyar/box v0.0.0
+	github.com/shopspring/decimal v1.4.0
+)
+
+replace github.com/sevlyar/box => ../
@@ -1,4 +1,18 @@
 /*
 Package box provides generic types to reduce memory footprint and GC pressure.
+
+# Decoding errors
+
+All the box types follow the same rule when decoding (UnmarshalJSON, Scan, etc.) fails:
+the error is returned and the value is reset to [None]. A value is never [Some] after a failed decoding.
+
+# Concurrency
+
+Values of the box types are plain values: methods with value receivers don't modify them,
+so the same value can be read and marshalled from multiple goroutines concurrently.
+Methods with pointer receivers (UnmarshalJSON, Scan, Reset, etc.) modify the value and must not
+be called concurrently with any other method on the same value.
+[RegisterMarshaler] and [SetTimeFormat] are safe for concurrent use, but should be called
+during program initialization, before marshalling starts. [LoadingOptional] is safe for concurrent use.
 */
 package box
@@ -0,0 +1,105 @@
+package box
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"testing"
+)
+
+// testRowsDB returns rows of the given columns served by in-memory driver.
+func testRowsDB(t *testing.T, cols []string, rows [][]driver.Value) *sql.Rows {
+	t.Helper()
+
+	db := sql.OpenDB(testConnector{cols: cols, rows: rows})
+	t.Cleanup(func() { _ = db.Close() })
+
+	res, err := db.Query("SELECT")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = res.Close() })
+
+	return res
+}
+
+type testConnector struct {
+	cols []string
+	rows [][]driver.Value
+}
+
+func (c testConnector) Connect(context.Context) (driver.Conn, error) {
+	return testConn{c}, nil
+}
+
+func (c testConnector) Driver() driver.Driver {
+	return testDriver{}
+}
+
+type testDriver struct{}
+
+func (testDriver) Open(string) (driver.Conn, error) {
+	return nil, errors.New("use testConnector")
+}
+
+type testConn struct {
+	c testConnector
+}
+
+func (c testConn) Prepare(string) (driver.Stmt, error) {
+	return testStmt(c), nil
+}
+
+func (testConn) Close() error {
+	return nil
+}
+
+func (testConn) Begin() (driver.Tx, error) {
+	return nil, errors.New("transactions are not supported")
+}
+
+type testStmt struct {
+	c testConnector
+}
+
+func (testStmt) Close() error {
+	return nil
+}
+
+func (testStmt) NumInput() int {
+	return -1
+}
+
+func (testStmt) Exec([]driver.Value) (driver.Result, error) {
+	return nil, errors.New("exec is not supported")
+}
+
+func (s testStmt) Query([]driver.Value) (driver.Rows, error) {
+	return &testRows{cols: s.c.cols, rows: s.c.rows}, nil
+}
+
+type testRows struct {
+	cols []string
+	rows [][]driver.Value
+}
+
+func (r *testRows) Columns() []string {
+	return r.cols
+}
+
+func (r *testRows) Close() error {
+	return nil
+}
+
+func (r *testRows) Next(dest []driver.Value) error {
+	if len(r.rows) == 0 {
+		return io.EOF
+	}
+
+	copy(dest, r.rows[0])
+	r.rows = r.rows[1:]
+
+	return nil
+}
@@ -0,0 +1,265 @@
+package box
+
+import (
+	"cmp"
+	"iter"
+	"strings"
+	"time"
+)
+
+// CoerceNum converts the value of the [Optional] to another numeric type preserving presence.
+// The conversion follows Go conversion rules: narrowing integer conversions truncate high bits,
+// float to integer conversions truncate the fraction, and the result is implementation-specific
+// if the value doesn't fit the target type.
+func CoerceNum[From, To Numeric](opt Optional[From]) Optional[To] {
+	if !opt.some {
+		return None[To]()
+	}
+
+	return Some(To(opt.v))
+}
+
+// Coalesce returns the first [Some] value of the arguments, or [None] if all of them are [None].
+// Like SQL COALESCE, it picks the first non-NULL value.
+func Coalesce[T any](opts ...Optional[T]) Optional[T] {
+	for _, opt := range opts {
+		if opt.some {
+			return opt
+		}
+	}
+
+	return None[T]()
+}
+
+// EqualTime reports whether both [Optional] values are [None], or both are [Some] and
+// represent the same time instant. Unlike ==, it ignores the location and the monotonic clock reading.
+func EqualTime(a, b Optional[time.Time]) bool {
+	if a.some != b.some {
+		return false
+	}
+
+	return !a.some || a.v.Equal(b.v)
+}
+
+// DerefPtr returns the [Optional] the pointer refers to, or [None] if the pointer is nil.
+//
+// A field of type *Optional[T] has three states: nil pointer, pointer to [None] and pointer to [Some].
+// Both nil pointer and pointer to [None] are presented in JSON as null, so the difference between them
+// is lost after a round-trip; DerefPtr treats them the same way.
+func DerefPtr[T any](p *Optional[T]) Optional[T] {
+	if p == nil {
+		return None[T]()
+	}
+
+	return *p
+}
+
+// Ap applies the function to the value if both of them are [Some], otherwise returns [None].
+func Ap[T, U any](fn Optional[func(T) U], arg Optional[T]) Optional[U] {
+	if !fn.some || !arg.some {
+		return None[U]()
+	}
+
+	return Some(fn.v(arg.v))
+}
+
+// ClampedGet returns def if [Optional] is [None], otherwise the underlying value clamped to [min, max].
+func ClampedGet[T cmp.Ordered](opt Optional[T], min, max, def T) T {
+	if !opt.some {
+		return def
+	}
+
+	switch {
+	case opt.v < min:
+		return min
+	case opt.v > max:
+		return max
+	}
+
+	return opt.v
+}
+
+// Pipeline is a chain of steps over an [Optional] value, see [Pipe].
+type Pipeline[T any] struct {
+	opt Optional[T]
+}
+
+// Pipe starts a [Pipeline] with the given value:
+//
+//	res := box.Pipe(opt).Then(parse).Then(validate).Result()
+func Pipe[T any](opt Optional[T]) Pipeline[T] {
+	return Pipeline[T]{opt}
+}
+
+// Then applies f to the current value. Once a step returns [None], the following steps are skipped.
+func (p Pipeline[T]) Then(f func(T) Optional[T]) Pipeline[T] {
+	if !p.opt.some {
+		return p
+	}
+
+	return Pipeline[T]{f(p.opt.v)}
+}
+
+// Result returns the value of the [Pipeline].
+func (p Pipeline[T]) Result() Optional[T] {
+	return p.opt
+}
+
+// EqualFold reports whether both [Optional] values are [None], or both are [Some] and
+// equal under simple Unicode case-folding, see [strings.EqualFold].
+func EqualFold(a, b Optional[string]) bool {
+	if a.some != b.some {
+		return false
+	}
+
+	return !a.some || strings.EqualFold(a.v, b.v)
+}
+
+// Map returns Some(f(v)) if [Optional] is Some(v), otherwise [None]. f is called only for [Some].
+func Map[T, U any](opt Optional[T], f func(T) U) Optional[U] {
+	if !opt.some {
+		return None[U]()
+	}
+
+	return Some(f(opt.v))
+}
+
+// FlatMap returns f(v) if [Optional] is Some(v), otherwise [None]. f is called only for [Some].
+func FlatMap[T, U any](opt Optional[T], f func(T) Optional[U]) Optional[U] {
+	if !opt.some {
+		return None[U]()
+	}
+
+	return f(opt.v)
+}
+
+// MapOpt returns Some(u) if [Optional] is Some(v) and f(v) returns (u, true), otherwise [None].
+// It combines [Map] and [Optional.Filter] in one pass. f is called only for [Some].
+func MapOpt[T, U any](opt Optional[T], f func(T) (U, bool)) Optional[U] {
+	if !opt.some {
+		return None[U]()
+	}
+
+	u, ok := f(opt.v)
+	if !ok {
+		return None[U]()
+	}
+
+	return Some(u)
+}
+
+// Cast returns Some(v.(U)) and true if [Optional] is Some(v) and v holds U,
+// [None] and false if v doesn't hold U. [None] is cast to [None] and true.
+func Cast[T, U any](opt Optional[T]) (Optional[U], bool) {
+	if !opt.some {
+		return None[U](), true
+	}
+
+	u, ok := any(opt.v).(U)
+	if !ok {
+		return None[U](), false
+	}
+
+	return Some(u), true
+}
+
+// Zip returns [Some] with both values if both [Optional] values are [Some], otherwise [None].
+func Zip[A, B any](a Optional[A], b Optional[B]) (res Optional[struct {
+	A A
+	B B
+}]) {
+	if !a.some || !b.some {
+		return res
+	}
+
+	res.some = true
+	res.v.A, res.v.B = a.v, b.v
+	return res
+}
+
+// Zip3 returns [Some] with all three values if all [Optional] values are [Some], otherwise [None].
+func Zip3[A, B, C any](a Optional[A], b Optional[B], c Optional[C]) (res Optional[struct {
+	A A
+	B B
+	C C
+}]) {
+	if !a.some || !b.some || !c.some {
+		return res
+	}
+
+	res.some = true
+	res.v.A, res.v.B, res.v.C = a.v, b.v, c.v
+	return res
+}
+
+// Match returns onSome(v) if [Optional] is Some(v), otherwise onNone().
+// Exactly one of the functions is called.
+func Match[T, U any](opt Optional[T], onSome func(T) U, onNone func() U) U {
+	if !opt.some {
+		return onNone()
+	}
+
+	return onSome(opt.v)
+}
+
+// All returns an iterator yielding the underlying value once if [Optional] is [Some],
+// and nothing if it is [None].
+func (opt Optional[T]) All() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		if opt.some {
+			yield(opt.v)
+		}
+	}
+}
+
+// IfSome calls f with the underlying value if [Optional] is [Some].
+func (opt Optional[T]) IfSome(f func(T)) {
+	if opt.some {
+		f(opt.v)
+	}
+}
+
+// IfNone calls f if [Optional] is [None].
+func (opt Optional[T]) IfNone(f func()) {
+	if !opt.some {
+		f()
+	}
+}
+
+// Filter returns the [Optional] if it is [Some] and its value satisfies pred, otherwise [None].
+// pred is called only for [Some].
+func (opt Optional[T]) Filter(pred func(T) bool) Optional[T] {
+	if !opt.some || pred(opt.v) {
+		return opt
+	}
+
+	return None[T]()
+}
+
+// Or returns the [Optional] if it is [Some], otherwise other.
+func (opt Optional[T]) Or(other Optional[T]) Optional[T] {
+	if opt.some {
+		return opt
+	}
+
+	return other
+}
+
+// OrElse returns the [Optional] if it is [Some], otherwise the result of f.
+// f is called only if the [Optional] is [None].
+func (opt Optional[T]) OrElse(f func() Optional[T]) Optional[T] {
+	if opt.some {
+		return opt
+	}
+
+	return f()
+}
+
+// And returns other if the [Optional] is [Some], otherwise [None].
+func (opt Optional[T]) And(other Optional[T]) Optional[T] {
+	if !opt.some {
+		return None[T]()
+	}
+
+	return other
+}
@@ -0,0 +1,459 @@
+package box
+
+import (
+	"encoding/json"
+	"reflect"
+	"slices"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestCoerceNum(t *testing.T) {
+	if got := CoerceNum[int32, int64](Some[int32](-7)); got != Some[int64](-7) {
+		t.Errorf("widening: got %v, want Some(-7)", got)
+	}
+	if got := CoerceNum[int32, int64](None[int32]()); got != None[int64]() {
+		t.Errorf("widening: got %v, want None", got)
+	}
+	if got := CoerceNum[int64, int8](Some[int64](300)); got != Some[int8](44) {
+		t.Errorf("narrowing: got %v, want Some(44)", got)
+	}
+	if got := CoerceNum[float64, int](Some(2.9)); got != Some(2) {
+		t.Errorf("float to int: got %v, want Some(2)", got)
+	}
+	if got := CoerceNum[float64, int](None[float64]()); got != None[int]() {
+		t.Errorf("float to int: got %v, want None", got)
+	}
+}
+
+func TestCoalesce(t *testing.T) {
+	tests := []struct {
+		name string
+		in   []Optional[string]
+		want Optional[string]
+	}{
+		{"mixed", []Optional[string]{None[string](), Some("a"), Some("b")}, Some("a")},
+		{"all none", []Optional[string]{None[string](), None[string]()}, None[string]()},
+		{"empty", nil, None[string]()},
+	}
+
+	for _, tt := range tests {
+		if got := Coalesce(tt.in...); got != tt.want {
+			t.Errorf("%s: Coalesce() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestEqualTime(t *testing.T) {
+	now := time.Now()
+	stripped := now.Round(0)
+
+	if Some(now) == Some(stripped) {
+		t.Fatal("test requires times differing by monotonic reading")
+	}
+
+	tests := []struct {
+		name string
+		a, b Optional[time.Time]
+		want bool
+	}{
+		{"monotonic", Some(now), Some(stripped), true},
+		{"location", Some(now), Some(now.UTC()), true},
+		{"different", Some(now), Some(now.Add(time.Second)), false},
+		{"mixed", Some(now), None[time.Time](), false},
+		{"none", None[time.Time](), None[time.Time](), true},
+	}
+
+	for _, tt := range tests {
+		if got := EqualTime(tt.a, tt.b); got != tt.want {
+			t.Errorf("%s: EqualTime() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestDerefPtr(t *testing.T) {
+	none := None[int]()
+	some := Some(5)
+
+	tests := []struct {
+		name string
+		in   *Optional[int]
+		want Optional[int]
+		json string
+	}{
+		{"nil pointer", nil, None[int](), `{"V":null}`},
+		{"pointer to none", &none, None[int](), `{"V":null}`},
+		{"pointer to some", &some, Some(5), `{"V":5}`},
+	}
+
+	for _, tt := range tests {
+		if got := DerefPtr(tt.in); got != tt.want {
+			t.Errorf("%s: DerefPtr() = %v, want %v", tt.name, got, tt.want)
+		}
+
+		b, err := json.Marshal(struct{ V *Optional[int] }{tt.in})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(b) != tt.json {
+			t.Errorf("%s: marshalled to %s, want %s", tt.name, b, tt.json)
+		}
+	}
+}
+
+func TestAp(t *testing.T) {
+	double := Some(func(v int) int { return v * 2 })
+	noFn := None[func(int) int]()
+
+	tests := []struct {
+		name string
+		fn   Optional[func(int) int]
+		arg  Optional[int]
+		want Optional[int]
+	}{
+		{"both", double, Some(3), Some(6)},
+		{"no arg", double, None[int](), None[int]()},
+		{"no fn", noFn, Some(3), None[int]()},
+		{"none", noFn, None[int](), None[int]()},
+	}
+
+	for _, tt := range tests {
+		if got := Ap(tt.fn, tt.arg); got != tt.want {
+			t.Errorf("%s: Ap() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestClampedGet(t *testing.T) {
+	tests := []struct {
+		name string
+		in   Optional[int]
+		want int
+	}{
+		{"below", Some(0), 1},
+		{"in range", Some(50), 50},
+		{"above", Some(500), 100},
+		{"none", None[int](), 20},
+	}
+
+	for _, tt := range tests {
+		if got := ClampedGet(tt.in, 1, 100, 20); got != tt.want {
+			t.Errorf("%s: ClampedGet() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestPipe(t *testing.T) {
+	var steps []string
+	step := func(name string, keep bool) func(int) Optional[int] {
+		return func(v int) Optional[int] {
+			steps = append(steps, name)
+			if !keep {
+				return None[int]()
+			}
+			return Some(v + 1)
+		}
+	}
+
+	got := Pipe(Some(1)).Then(step("a", true)).Then(step("b", true)).Result()
+	if got != Some(3) {
+		t.Errorf("complete: got %v, want Some(3)", got)
+	}
+
+	steps = nil
+	got = Pipe(Some(1)).Then(step("a", true)).Then(step("b", false)).Then(step("c", true)).Result()
+	if got != None[int]() {
+		t.Errorf("short-circuit: got %v, want None", got)
+	}
+	if want := []string{"a", "b"}; !reflect.DeepEqual(steps, want) {
+		t.Errorf("short-circuit: steps %v, want %v", steps, want)
+	}
+
+	steps = nil
+	if got := Pipe(None[int]()).Then(step("a", true)).Result(); got != None[int]() || len(steps) != 0 {
+		t.Errorf("none: got %v with steps %v", got, steps)
+	}
+}
+
+func TestEqualFold(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b Optional[string]
+		want bool
+	}{
+		{"differing case", Some("John@Example.com"), Some("john@example.com"), true},
+		{"differing value", Some("john"), Some("jane"), false},
+		{"mixed", Some("john"), None[string](), false},
+		{"mixed empty", None[string](), Some(""), false},
+		{"none", None[string](), None[string](), true},
+	}
+
+	for _, tt := range tests {
+		if got := EqualFold(tt.a, tt.b); got != tt.want {
+			t.Errorf("%s: EqualFold() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestMap(t *testing.T) {
+	calls := 0
+	length := func(s string) int {
+		calls++
+		return len(s)
+	}
+
+	if got := Map(Some("abc"), length); got != Some(3) {
+		t.Errorf("Some: got %v, want Some(3)", got)
+	}
+	if calls != 1 {
+		t.Errorf("Some: f called %d times, want 1", calls)
+	}
+
+	if got := Map(None[string](), length); got != None[int]() {
+		t.Errorf("None: got %v, want None", got)
+	}
+	if calls != 1 {
+		t.Errorf("None: f called %d times, want 0", calls-1)
+	}
+}
+
+func TestFlatMap(t *testing.T) {
+	calls := 0
+	positive := func(v int) Optional[int] {
+		calls++
+		if v <= 0 {
+			return None[int]()
+		}
+		return Some(v)
+	}
+
+	if got := FlatMap(Some(5), positive); got != Some(5) {
+		t.Errorf("f returns Some: got %v, want Some(5)", got)
+	}
+	if got := FlatMap(Some(-5), positive); got != None[int]() {
+		t.Errorf("f returns None: got %v, want None", got)
+	}
+
+	calls = 0
+	if got := FlatMap(None[int](), positive); got != None[int]() {
+		t.Errorf("None: got %v, want None", got)
+	}
+	if calls != 0 {
+		t.Errorf("None: f called %d times, want 0", calls)
+	}
+}
+
+func TestOptional_Filter(t *testing.T) {
+	calls := 0
+	even := func(v int) bool {
+		calls++
+		return v%2 == 0
+	}
+
+	opt := Some(4)
+	if got := opt.Filter(even); got != opt {
+		t.Errorf("pass: got %v, want %v", got, opt)
+	}
+	if got := Some(3).Filter(even); got != None[int]() {
+		t.Errorf("fail: got %v, want None", got)
+	}
+
+	calls = 0
+	if got := None[int]().Filter(even); got != None[int]() {
+		t.Errorf("None: got %v, want None", got)
+	}
+	if calls != 0 {
+		t.Errorf("None: pred called %d times, want 0", calls)
+	}
+}
+
+func TestOptional_OrAnd(t *testing.T) {
+	a, b, none := Some(1), Some(2), None[int]()
+
+	tests := []struct {
+		name    string
+		x, y    Optional[int]
+		or, and Optional[int]
+	}{
+		{"some some", a, b, a, b},
+		{"some none", a, none, a, none},
+		{"none some", none, b, b, none},
+		{"none none", none, none, none, none},
+	}
+
+	for _, tt := range tests {
+		if got := tt.x.Or(tt.y); got != tt.or {
+			t.Errorf("%s: Or() = %v, want %v", tt.name, got, tt.or)
+		}
+		if got := tt.x.OrElse(func() Optional[int] { return tt.y }); got != tt.or {
+			t.Errorf("%s: OrElse() = %v, want %v", tt.name, got, tt.or)
+		}
+		if got := tt.x.And(tt.y); got != tt.and {
+			t.Errorf("%s: And() = %v, want %v", tt.name, got, tt.and)
+		}
+	}
+
+	called := false
+	a.OrElse(func() Optional[int] {
+		called = true
+		return b
+	})
+	if called {
+		t.Error("OrElse: f called for Some")
+	}
+}
+
+func TestMapOpt(t *testing.T) {
+	calls := 0
+	parsePort := func(s string) (int, bool) {
+		calls++
+		v, err := strconv.Atoi(s)
+		return v, err == nil && v > 0 && v < 65536
+	}
+
+	if got := MapOpt(Some("8080"), parsePort); got != Some(8080) {
+		t.Errorf("keep: got %v, want Some(8080)", got)
+	}
+	if got := MapOpt(Some("99999"), parsePort); got != None[int]() {
+		t.Errorf("drop: got %v, want None", got)
+	}
+
+	calls = 0
+	if got := MapOpt(None[string](), parsePort); got != None[int]() {
+		t.Errorf("None: got %v, want None", got)
+	}
+	if calls != 0 {
+		t.Errorf("None: f called %d times, want 0", calls)
+	}
+}
+
+func TestMatch(t *testing.T) {
+	var someCalls, noneCalls int
+	onSome := func(v int) string {
+		someCalls++
+		return strconv.Itoa(v)
+	}
+	onNone := func() string {
+		noneCalls++
+		return "none"
+	}
+
+	if got := Match(Some(5), onSome, onNone); got != "5" {
+		t.Errorf("Some: got %q, want %q", got, "5")
+	}
+	if someCalls != 1 || noneCalls != 0 {
+		t.Errorf("Some: calls onSome=%d onNone=%d, want 1 and 0", someCalls, noneCalls)
+	}
+
+	someCalls = 0
+	if got := Match(None[int](), onSome, onNone); got != "none" {
+		t.Errorf("None: got %q, want %q", got, "none")
+	}
+	if someCalls != 0 || noneCalls != 1 {
+		t.Errorf("None: calls onSome=%d onNone=%d, want 0 and 1", someCalls, noneCalls)
+	}
+}
+
+func TestOptional_IfSomeIfNone(t *testing.T) {
+	var (
+		someCalls, noneCalls int
+		got                  int
+	)
+	onSome := func(v int) {
+		someCalls++
+		got = v
+	}
+	onNone := func() { noneCalls++ }
+
+	Some(5).IfSome(onSome)
+	Some(5).IfNone(onNone)
+	if someCalls != 1 || noneCalls != 0 || got != 5 {
+		t.Errorf("Some: calls IfSome=%d IfNone=%d value=%d, want 1, 0, 5", someCalls, noneCalls, got)
+	}
+
+	someCalls = 0
+	None[int]().IfSome(onSome)
+	None[int]().IfNone(onNone)
+	if someCalls != 0 || noneCalls != 1 {
+		t.Errorf("None: calls IfSome=%d IfNone=%d, want 0 and 1", someCalls, noneCalls)
+	}
+}
+
+func TestCast(t *testing.T) {
+	type stringer interface{ String() string }
+
+	opt := Some[stringer](time.Second)
+	if got, ok := Cast[stringer, time.Duration](opt); !ok || got != Some(time.Second) {
+		t.Errorf("success: got %v, %v, want Some(1s), true", got, ok)
+	}
+	if got, ok := Cast[stringer, time.Month](opt); ok || got != None[time.Month]() {
+		t.Errorf("failure: got %v, %v, want None, false", got, ok)
+	}
+	if got, ok := Cast[stringer, time.Duration](None[stringer]()); !ok || got != None[time.Duration]() {
+		t.Errorf("None: got %v, %v, want None, true", got, ok)
+	}
+}
+
+func TestOptional_All(t *testing.T) {
+	n := 0
+	for v := range Some(5).All() {
+		n++
+		if v != 5 {
+			t.Errorf("Some: got %v, want 5", v)
+		}
+	}
+	if n != 1 {
+		t.Errorf("Some: yielded %d times, want 1", n)
+	}
+
+	for range None[int]().All() {
+		t.Error("None: yielded a value")
+	}
+
+	for range Some(5).All() {
+		break
+	}
+
+	if got := slices.Collect(Some("a").All()); !slices.Equal(got, []string{"a"}) {
+		t.Errorf("Collect: got %v, want [a]", got)
+	}
+}
+
+func TestZip(t *testing.T) {
+	got := Zip(Some(1), Some("a"))
+	if !got.IsSome() || got.Get().A != 1 || got.Get().B != "a" {
+		t.Errorf("all present: got %v, want Some({1 a})", got)
+	}
+	if got := Zip(None[int](), Some("a")); got.IsSome() {
+		t.Errorf("first missing: got %v, want None", got)
+	}
+	if got := Zip(Some(1), None[string]()); got.IsSome() {
+		t.Errorf("second missing: got %v, want None", got)
+	}
+}
+
+func TestZip3(t *testing.T) {
+	got := Zip3(Some(1), Some("a"), Some(true))
+	if v, ok := got.Take(); !ok || v.A != 1 || v.B != "a" || !v.C {
+		t.Errorf("all present: got %v, want Some({1 a true})", got)
+	}
+
+	tests := []struct {
+		name string
+		a    Optional[int]
+		b    Optional[string]
+		c    Optional[bool]
+	}{
+		{"first missing", None[int](), Some("a"), Some(true)},
+		{"second missing", Some(1), None[string](), Some(true)},
+		{"third missing", Some(1), Some("a"), None[bool]()},
+		{"all missing", None[int](), None[string](), None[bool]()},
+	}
+
+	for _, tt := range tests {
+		if got := Zip3(tt.a, tt.b, tt.c); got.IsSome() {
+			t.Errorf("%s: got %v, want None", tt.name, got)
+		}
+	}
+}
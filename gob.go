@@ -0,0 +1,67 @@
+package box
+
+import (
+	"bytes"
+	"encoding/gob"
+	"errors"
+)
+
+var (
+	_ gob.GobEncoder = Optional[any]{}
+	_ gob.GobDecoder = (*Optional[any])(nil)
+)
+
+// Presence markers of the gob encoding of [Optional].
+const (
+	gobNone byte = iota
+	gobSome
+)
+
+// GobEncode implements [gob.GobEncoder]. [None] value is encoded as a single marker byte,
+// [Some] value as a marker byte followed by the gob encoding of the underlying value.
+//
+// Limitations:
+//   - gob never transmits struct fields holding zero values and [None] is the zero value
+//     of [Optional], so a [None] field is not transmitted at all. Decoding into a reused
+//     destination leaves its field unchanged, e.g. a stale [Some]. Always decode into
+//     a zero value (or reset the destination) to receive [None] fields as [None].
+//   - Each [Some] value is encoded as a separate gob stream, so the type description
+//     of T is repeated for every value, which adds noticeable overhead to large payloads.
+func (opt Optional[T]) GobEncode() ([]byte, error) {
+	if !opt.some {
+		return []byte{gobNone}, nil
+	}
+
+	buf := bytes.NewBuffer([]byte{gobSome})
+	if err := gob.NewEncoder(buf).Encode(&opt.v); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// GobDecode implements [gob.GobDecoder].
+// In case of error the [Optional] is reset to [None] and the error is returned.
+func (opt *Optional[T]) GobDecode(data []byte) error {
+	if len(data) == 0 {
+		*opt = None[T]()
+		return errors.New("box: empty gob data")
+	}
+
+	switch data[0] {
+	case gobNone:
+		*opt = None[T]()
+		return nil
+	case gobSome:
+		var v T
+		if err := gob.NewDecoder(bytes.NewReader(data[1:])).Decode(&v); err != nil {
+			*opt = None[T]()
+			return err
+		}
+		*opt = Some(v)
+		return nil
+	}
+
+	*opt = None[T]()
+	return errors.New("box: invalid gob presence marker")
+}
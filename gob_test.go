@@ -0,0 +1,91 @@
+package box
+
+import (
+	"bytes"
+	"encoding/gob"
+	"reflect"
+	"testing"
+)
+
+func TestOptional_Gob(t *testing.T) {
+	type point struct{ X, Y int }
+	type message struct {
+		ID     Optional[int]
+		Name   Optional[string]
+		Pos    Optional[point]
+		Tags   Optional[[]string]
+		Absent Optional[point]
+	}
+
+	in := message{
+		ID:   Some(0),
+		Name: Some(""),
+		Pos:  Some(point{1, 2}),
+		Tags: Some([]string{"a", "b"}),
+	}
+
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(in); err != nil {
+		t.Fatal(err)
+	}
+
+	var out message
+	if err := gob.NewDecoder(&buf).Decode(&out); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(out, in) {
+		t.Errorf("got %+v, want %+v", out, in)
+	}
+}
+
+func TestOptional_GobEncode_none(t *testing.T) {
+	b, err := None[string]().GobEncode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(b) != 1 {
+		t.Errorf("None is encoded into %d bytes, want 1", len(b))
+	}
+}
+
+func TestOptional_GobDecode_errors(t *testing.T) {
+	for _, data := range [][]byte{nil, {0xff}, {gobSome, 0x01}} {
+		opt := Some(1)
+		if err := opt.GobDecode(data); err == nil {
+			t.Errorf("%x: expected error", data)
+		}
+		if opt != None[int]() {
+			t.Errorf("%x: got %v, want None after error", data, opt)
+		}
+	}
+}
+
+func TestOptional_Gob_reusedDestination(t *testing.T) {
+	type message struct {
+		Name Optional[string]
+		Age  Optional[int]
+	}
+
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(message{Age: Some(1)}); err != nil {
+		t.Fatal(err)
+	}
+	data := buf.Bytes()
+
+	// The None field isn't transmitted, so a reused destination keeps its stale value.
+	out := message{Name: Some("stale")}
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&out); err != nil {
+		t.Fatal(err)
+	}
+	if want := (message{Name: Some("stale"), Age: Some(1)}); out != want {
+		t.Errorf("reused: got %+v, want %+v", out, want)
+	}
+
+	out = message{}
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&out); err != nil {
+		t.Fatal(err)
+	}
+	if want := (message{Age: Some(1)}); out != want {
+		t.Errorf("zero: got %+v, want %+v", out, want)
+	}
+}
@@ -0,0 +1,599 @@
+package box
+
+import (
+	"bufio"
+	"bytes"
+	"cmp"
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// FromJSONField extracts the field with the given key from the JSON object.
+// Returns [None] if the field is absent or null, otherwise decodes the field value into T.
+func FromJSONField[T any](data []byte, key string) (Optional[T], error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return None[T](), err
+	}
+
+	raw, ok := fields[key]
+	if !ok || bytes.Equal(raw, nullStrBytes) {
+		return None[T](), nil
+	}
+
+	var v T
+	if err := json.Unmarshal(raw, &v); err != nil {
+		return None[T](), err
+	}
+
+	return Some(v), nil
+}
+
+// DecodeContext holds the decoding policy for [UnmarshalCtx].
+// Zero value of DecodeContext means strict decoding, same as [encoding/json] does.
+// DecodeContext is usually configured once per request handler.
+type DecodeContext struct {
+	// LenientNumbers allows numbers to be presented as JSON strings, e.g. "42".
+	LenientNumbers bool
+	// AllowUndefined treats empty input and the `undefined` token as [None] instead of an error.
+	AllowUndefined bool
+	// EmptyStringAsNone treats empty JSON string as [None].
+	EmptyStringAsNone bool
+}
+
+var (
+	undefinedStrBytes   = []byte("undefined")
+	emptyStringStrBytes = []byte(`""`)
+)
+
+// UnmarshalCtx decodes JSON data into [Optional] according to the policy of the given context.
+// JSON null is always decoded as [None].
+func UnmarshalCtx[T any](ctx DecodeContext, data []byte) (Optional[T], error) {
+	data = bytes.TrimSpace(data)
+
+	switch {
+	case bytes.Equal(data, nullStrBytes):
+		return None[T](), nil
+	case ctx.AllowUndefined && (len(data) == 0 || bytes.Equal(data, undefinedStrBytes)):
+		return None[T](), nil
+	case ctx.EmptyStringAsNone && bytes.Equal(data, emptyStringStrBytes):
+		return None[T](), nil
+	}
+
+	var v T
+	err := json.Unmarshal(data, &v)
+	if err != nil && ctx.LenientNumbers {
+		if num, ok := quotedNumber(data); ok {
+			err = json.Unmarshal(num, &v)
+		}
+	}
+	if err != nil {
+		return None[T](), err
+	}
+
+	return Some(v), nil
+}
+
+// quotedNumber returns the content of the JSON string if it is a valid JSON number.
+func quotedNumber(data []byte) ([]byte, bool) {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, false
+	}
+
+	var num json.Number
+	if err := json.Unmarshal([]byte(s), &num); err != nil {
+		return nil, false
+	}
+
+	return []byte(num), true
+}
+
+// MarshalCompactArray encodes the [Some] values of the slice as JSON array, [None] values are dropped.
+// An empty or all-[None] slice is encoded as [].
+func MarshalCompactArray[T any](opts []Optional[T]) ([]byte, error) {
+	values := make([]T, 0, len(opts))
+	for _, opt := range opts {
+		if opt.some {
+			values = append(values, opt.v)
+		}
+	}
+
+	return json.Marshal(values)
+}
+
+// SetJSON decodes JSON data into the [Optional]. JSON null resets it to [None].
+// SetJSON is the same as UnmarshalJSON, but has a clearer name to be called directly.
+// It reuses the storage of the current value, so slices keep their backing arrays between calls.
+// Decoding follows [json.Unmarshal] rules for non-empty targets: fields of a struct and keys of a map
+// absent in data keep their current values.
+// In case of error the [Optional] is reset to [None].
+func (opt *Optional[T]) SetJSON(data []byte) error {
+	return opt.UnmarshalJSON(data)
+}
+
+// Encode writes the [Optional] to enc: the underlying value if [Optional] is [Some], otherwise null.
+// Unlike encoding of the [Optional] itself, the value is encoded by enc directly,
+// so its settings (e.g. [json.Encoder.SetEscapeHTML]) apply to the value too.
+// A function registered by [RegisterMarshaler] takes precedence as in [Optional.MarshalJSON].
+func (opt Optional[T]) Encode(enc *json.Encoder) error {
+	if !opt.some {
+		return enc.Encode(nil)
+	}
+
+	if f, ok := registeredMarshaler[T](); ok {
+		b, err := f(opt.v)
+		if err != nil {
+			return err
+		}
+		return enc.Encode(json.RawMessage(b))
+	}
+
+	return enc.Encode(opt.v)
+}
+
+var (
+	marshalers      sync.Map // reflect.Type -> func(T) ([]byte, error)
+	marshalersCount atomic.Int64
+)
+
+// RegisterMarshaler registers the JSON encoding function used by [Optional.MarshalJSON]
+// for [Some] values of type T, e.g. to present all Optional[Money] values as strings.
+// A later registration for the same type replaces the previous one.
+// Types without registered function are encoded with [json.Marshal].
+// RegisterMarshaler is intended to be called during program initialization.
+func RegisterMarshaler[T any](f func(T) ([]byte, error)) {
+	if _, loaded := marshalers.Swap(reflect.TypeFor[T](), f); !loaded {
+		marshalersCount.Add(1)
+	}
+}
+
+// UnregisterMarshaler removes the function registered for type T by [RegisterMarshaler],
+// so values of type T are encoded with [json.Marshal] again.
+func UnregisterMarshaler[T any]() {
+	if _, loaded := marshalers.LoadAndDelete(reflect.TypeFor[T]()); loaded {
+		marshalersCount.Add(-1)
+	}
+}
+
+// registeredMarshaler returns the encoding function registered for type T.
+// The lookup is skipped while nothing is registered.
+func registeredMarshaler[T any]() (func(T) ([]byte, error), bool) {
+	if marshalersCount.Load() == 0 {
+		return nil, false
+	}
+
+	f, ok := marshalers.Load(reflect.TypeFor[T]())
+	if !ok {
+		return nil, false
+	}
+
+	return f.(func(T) ([]byte, error)), true
+}
+
+// SetTimeFormat sets the layout used by [Optional.MarshalJSON] to present [Some] values
+// of type [time.Time] as JSON strings, e.g. [time.DateTime]. An empty layout restores
+// the default encoding of [time.Time]. It affects only the box types and replaces
+// the function registered for [time.Time] by [RegisterMarshaler], as registration does.
+// Decoding is not affected. SetTimeFormat is intended to be called during program initialization.
+func SetTimeFormat(layout string) {
+	if layout == "" {
+		UnregisterMarshaler[time.Time]()
+		return
+	}
+
+	RegisterMarshaler(func(t time.Time) ([]byte, error) {
+		return json.Marshal(t.Format(layout))
+	})
+}
+
+// EncodeStructOmitNone encodes v to JSON like [json.Marshal] does, but omits every struct field
+// holding [None] at any depth, including nested structs, pointers, slices and arrays of structs,
+// without requiring `json:",omitzero"` annotations. Field names and the "-", omitempty, omitzero
+// and string options of json tags are respected, embedded structs are handled by the rules of [json.Marshal].
+// Values implementing [json.Marshaler] or [encoding.TextMarshaler] and maps are encoded with [json.Marshal].
+// Variants of [Optional] with their own JSON encoding (e.g. [Tagged]) are encoded with their
+// MarshalJSON method, [None] values of them are omitted unless they are encoded as something
+// other than null ([EmptyStringNull] and [WithNoneRepr]).
+func EncodeStructOmitNone(v any) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := encodeOmitNone(&buf, reflect.ValueOf(v)); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+var (
+	marshalerType     = reflect.TypeFor[json.Marshaler]()
+	textMarshalerType = reflect.TypeFor[encoding.TextMarshaler]()
+)
+
+// customJSON is implemented by the variants of [Optional] having their own JSON encoding,
+// so they can't be unwrapped as [Optional] by [EncodeStructOmitNone].
+type customJSON interface {
+	customJSON()
+}
+
+var customJSONType = reflect.TypeFor[customJSON]()
+
+// customNoneJSON is implemented by the variants of [Optional] encoding [None] as something
+// other than null, so [EncodeStructOmitNone] keeps their [None] fields.
+type customNoneJSON interface {
+	customNoneJSON()
+}
+
+var customNoneJSONType = reflect.TypeFor[customNoneJSON]()
+
+// isOptionalJSON reports whether values of type t are encoded to JSON as [Optional] is.
+func isOptionalJSON(t reflect.Type) bool {
+	return t.Implements(reflectValuerType) && !t.Implements(customJSONType)
+}
+
+func encodeOmitNone(buf *bytes.Buffer, rv reflect.Value) error {
+	if !rv.IsValid() || (rv.Kind() == reflect.Pointer || rv.Kind() == reflect.Interface) && rv.IsNil() {
+		buf.Write(nullStrBytes)
+		return nil
+	}
+
+	if isOptionalJSON(rv.Type()) {
+		inner, some := rv.Interface().(reflectValuer).reflectValue()
+		if !some {
+			buf.Write(nullStrBytes)
+			return nil
+		}
+		if _, ok := marshalers.Load(inner.Type()); !ok {
+			// Optional.MarshalJSON encodes a copy of the value, so pointer-receiver
+			// marshalers of T are not used, the same for the unwrapped value.
+			return encodeOmitNone(buf, reflect.ValueOf(inner.Interface()))
+		}
+	}
+
+	t := rv.Type()
+	if t.Implements(marshalerType) || t.Implements(textMarshalerType) {
+		return encodeJSON(buf, rv.Interface())
+	}
+	if rv.CanAddr() && (reflect.PointerTo(t).Implements(marshalerType) || reflect.PointerTo(t).Implements(textMarshalerType)) {
+		return encodeJSON(buf, rv.Addr().Interface())
+	}
+
+	switch rv.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		return encodeOmitNone(buf, rv.Elem())
+	case reflect.Struct:
+		buf.WriteByte('{')
+		if err := encodeFieldsOmitNone(buf, rv); err != nil {
+			return err
+		}
+		buf.WriteByte('}')
+		return nil
+	case reflect.Slice:
+		if rv.IsNil() {
+			buf.Write(nullStrBytes)
+			return nil
+		}
+		if t.Elem().Kind() == reflect.Uint8 {
+			return encodeJSON(buf, rv.Interface())
+		}
+		fallthrough
+	case reflect.Array:
+		buf.WriteByte('[')
+		for i := range rv.Len() {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			if err := encodeOmitNone(buf, rv.Index(i)); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte(']')
+		return nil
+	}
+
+	return encodeJSON(buf, rv.Interface())
+}
+
+// encodeFieldsOmitNone writes the fields of the struct without braces.
+func encodeFieldsOmitNone(buf *bytes.Buffer, rv reflect.Value) error {
+	first := true
+	for _, f := range jsonFields(rv.Type()) {
+		fv, ok := fieldByIndex(rv, f.index)
+		if !ok || !fv.Type().Implements(customNoneJSONType) && isNone(fv) ||
+			hasTagOption(f.opts, "omitempty") && isEmptyValue(fv) ||
+			hasTagOption(f.opts, "omitzero") && isZeroValue(fv) {
+			continue
+		}
+
+		if !first {
+			buf.WriteByte(',')
+		}
+		first = false
+
+		if err := encodeJSON(buf, f.name); err != nil {
+			return err
+		}
+		buf.WriteByte(':')
+		encode := encodeOmitNone
+		if f.quoted {
+			encode = encodeQuoted
+		}
+		if err := encode(buf, fv); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// encodeQuoted encodes the scalar value as a JSON string like the ",string" option of json tags does.
+func encodeQuoted(buf *bytes.Buffer, rv reflect.Value) error {
+	if rv.Kind() == reflect.Pointer {
+		if rv.IsNil() {
+			buf.Write(nullStrBytes)
+			return nil
+		}
+		rv = rv.Elem()
+	}
+
+	t := rv.Type()
+	if t.Implements(marshalerType) || t.Implements(textMarshalerType) {
+		return encodeOmitNone(buf, rv)
+	}
+
+	b, err := json.Marshal(rv.Interface())
+	if err != nil {
+		return err
+	}
+	if rv.Kind() == reflect.String {
+		return encodeJSON(buf, string(b))
+	}
+
+	buf.WriteByte('"')
+	buf.Write(b)
+	buf.WriteByte('"')
+	return nil
+}
+
+// fieldByIndex returns the nested field like [reflect.Value.FieldByIndex] does,
+// or false if the path goes through a nil embedded pointer.
+func fieldByIndex(rv reflect.Value, index []int) (reflect.Value, bool) {
+	for i, x := range index {
+		if i > 0 && rv.Kind() == reflect.Pointer {
+			if rv.IsNil() {
+				return reflect.Value{}, false
+			}
+			rv = rv.Elem()
+		}
+		rv = rv.Field(x)
+	}
+
+	return rv, true
+}
+
+// jsonField describes a struct field encoded by [json.Marshal].
+type jsonField struct {
+	name   string
+	opts   string
+	tagged bool
+	quoted bool
+	index  []int
+	typ    reflect.Type
+}
+
+var jsonFieldsCache sync.Map // reflect.Type -> []jsonField
+
+// jsonFields returns the fields of the struct type encoded by [json.Marshal] in the encoding order.
+// It follows the rules of encoding/json: json:"-" fields are skipped, exported and unexported
+// embedded structs without a name in the tag are inlined, and among the promoted fields with
+// the same name only the shallowest one is kept, preferring a tagged one; ambiguous fields are dropped.
+func jsonFields(t reflect.Type) []jsonField {
+	if fields, ok := jsonFieldsCache.Load(t); ok {
+		return fields.([]jsonField)
+	}
+
+	var (
+		fields  []jsonField
+		next    = []jsonField{{typ: t}}
+		visited = map[reflect.Type]bool{}
+	)
+	for len(next) > 0 {
+		current := next
+		next = nil
+
+		levelVisited := map[reflect.Type]bool{}
+		for _, embedded := range current {
+			if visited[embedded.typ] {
+				continue
+			}
+			levelVisited[embedded.typ] = true
+
+			for i := range embedded.typ.NumField() {
+				sf := embedded.typ.Field(i)
+				ft := sf.Type
+				if ft.Name() == "" && ft.Kind() == reflect.Pointer {
+					ft = ft.Elem()
+				}
+				if sf.Anonymous {
+					if !sf.IsExported() && ft.Kind() != reflect.Struct {
+						continue
+					}
+				} else if !sf.IsExported() {
+					continue
+				}
+
+				tag := sf.Tag.Get("json")
+				if tag == "-" {
+					continue
+				}
+				name, opts, _ := strings.Cut(tag, ",")
+
+				index := append(append([]int(nil), embedded.index...), i)
+				if name != "" || !sf.Anonymous || ft.Kind() != reflect.Struct {
+					f := jsonField{name: name, opts: opts, tagged: name != "", index: index, typ: ft}
+					if hasTagOption(opts, "string") {
+						switch ft.Kind() {
+						case reflect.Bool,
+							reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+							reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+							reflect.Float32, reflect.Float64, reflect.String:
+							f.quoted = true
+						}
+					}
+					if f.name == "" {
+						f.name = sf.Name
+					}
+					fields = append(fields, f)
+					continue
+				}
+
+				next = append(next, jsonField{index: index, typ: ft})
+			}
+		}
+		for typ := range levelVisited {
+			visited[typ] = true
+		}
+	}
+
+	fields = dominantFields(fields)
+	jsonFieldsCache.Store(t, fields)
+
+	return fields
+}
+
+// dominantFields resolves the fields with the same name by the rules of encoding/json
+// and returns the remaining fields sorted by index sequence.
+func dominantFields(fields []jsonField) []jsonField {
+	slices.SortStableFunc(fields, func(a, b jsonField) int {
+		if c := strings.Compare(a.name, b.name); c != 0 {
+			return c
+		}
+		if c := cmp.Compare(len(a.index), len(b.index)); c != 0 {
+			return c
+		}
+		if a.tagged != b.tagged {
+			if a.tagged {
+				return -1
+			}
+			return 1
+		}
+		return slices.Compare(a.index, b.index)
+	})
+
+	res := fields[:0]
+	for i := 0; i < len(fields); {
+		j := i + 1
+		for j < len(fields) && fields[j].name == fields[i].name {
+			j++
+		}
+		group := fields[i:j]
+		if len(group) == 1 || len(group[0].index) != len(group[1].index) || group[0].tagged != group[1].tagged {
+			res = append(res, group[0])
+		}
+		i = j
+	}
+
+	slices.SortFunc(res, func(a, b jsonField) int {
+		return slices.Compare(a.index, b.index)
+	})
+
+	return res
+}
+
+func encodeJSON(buf *bytes.Buffer, v any) error {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	buf.Write(b)
+	return nil
+}
+
+func hasTagOption(opts, opt string) bool {
+	for opts != "" {
+		var o string
+		o, opts, _ = strings.Cut(opts, ",")
+		if o == opt {
+			return true
+		}
+	}
+
+	return false
+}
+
+// isEmptyValue reports whether the value is empty in terms of omitempty option of encoding/json.
+func isEmptyValue(rv reflect.Value) bool {
+	switch rv.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return rv.Len() == 0
+	case reflect.Bool, reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64, reflect.Interface, reflect.Pointer:
+		return rv.IsZero()
+	}
+
+	return false
+}
+
+// isZeroValue reports whether the value is zero in terms of omitzero option of encoding/json.
+func isZeroValue(rv reflect.Value) bool {
+	if z, ok := rv.Interface().(interface{ IsZero() bool }); ok {
+		if rv.Kind() == reflect.Pointer && rv.IsNil() {
+			return true
+		}
+		return z.IsZero()
+	}
+
+	return rv.IsZero()
+}
+
+// EncodeNDJSON writes the values as newline-delimited JSON, one value per line.
+// [None] values are written as null.
+func EncodeNDJSON[T any](w io.Writer, opts []Optional[T]) error {
+	enc := json.NewEncoder(w)
+	for _, opt := range opts {
+		if err := enc.Encode(opt); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// DecodeNDJSON reads newline-delimited JSON values, one value per line. Null lines become [None].
+// Blank lines are skipped.
+func DecodeNDJSON[T any](r io.Reader) ([]Optional[T], error) {
+	var (
+		res  []Optional[T]
+		line int
+	)
+
+	br := bufio.NewReader(r)
+	for {
+		data, readErr := br.ReadBytes('\n')
+		if readErr != nil && readErr != io.EOF {
+			return res, readErr
+		}
+		line++
+
+		if data = bytes.TrimSpace(data); len(data) > 0 {
+			var opt Optional[T]
+			if err := opt.SetJSON(data); err != nil {
+				return res, fmt.Errorf("line %d: %w", line, err)
+			}
+			res = append(res, opt)
+		}
+
+		if readErr == io.EOF {
+			return res, nil
+		}
+	}
+}
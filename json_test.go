@@ -0,0 +1,573 @@
+package box
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestFromJSONField(t *testing.T) {
+	data := []byte(`{"name": "John", "age": 42, "email": null}`)
+
+	tests := []struct {
+		key  string
+		want Optional[int]
+	}{
+		{"age", Some(42)},
+		{"email", None[int]()},
+		{"phone", None[int]()},
+	}
+
+	for _, tt := range tests {
+		got, err := FromJSONField[int](data, tt.key)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", tt.key, err)
+		}
+		if got != tt.want {
+			t.Errorf("%s: got %v, want %v", tt.key, got, tt.want)
+		}
+	}
+
+	if _, err := FromJSONField[int](data, "name"); err == nil {
+		t.Error("expected error on type mismatch")
+	}
+	if _, err := FromJSONField[int]([]byte(`[1, 2]`), "age"); err == nil {
+		t.Error("expected error on non-object input")
+	}
+}
+
+func TestUnmarshalCtx(t *testing.T) {
+	tests := []struct {
+		name    string
+		ctx     DecodeContext
+		data    string
+		want    Optional[int]
+		wantErr bool
+	}{
+		{"strict number", DecodeContext{}, `42`, Some(42), false},
+		{"strict null", DecodeContext{}, `null`, None[int](), false},
+		{"strict quoted number", DecodeContext{}, `"42"`, None[int](), true},
+		{"lenient quoted number", DecodeContext{LenientNumbers: true}, `"42"`, Some(42), false},
+		{"lenient quoted garbage", DecodeContext{LenientNumbers: true}, `"abc"`, None[int](), true},
+		{"strict empty", DecodeContext{}, ``, None[int](), true},
+		{"strict undefined", DecodeContext{}, `undefined`, None[int](), true},
+		{"allow empty", DecodeContext{AllowUndefined: true}, ``, None[int](), false},
+		{"allow undefined", DecodeContext{AllowUndefined: true}, `undefined`, None[int](), false},
+		{"strict empty string", DecodeContext{}, `""`, None[int](), true},
+		{"empty string as none", DecodeContext{EmptyStringAsNone: true}, `""`, None[int](), false},
+	}
+
+	for _, tt := range tests {
+		got, err := UnmarshalCtx[int](tt.ctx, []byte(tt.data))
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s: error = %v, wantErr %v", tt.name, err, tt.wantErr)
+		}
+		if got != tt.want {
+			t.Errorf("%s: got %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestUnmarshalCtxEmptyStringAsNone(t *testing.T) {
+	ctx := DecodeContext{EmptyStringAsNone: true}
+
+	got, err := UnmarshalCtx[string](ctx, []byte(`""`))
+	if err != nil || got != None[string]() {
+		t.Errorf(`"" decoded as %v, %v; want None`, got, err)
+	}
+
+	got, err = UnmarshalCtx[string](DecodeContext{}, []byte(`""`))
+	if err != nil || got != Some("") {
+		t.Errorf(`"" decoded as %v, %v; want Some("")`, got, err)
+	}
+}
+
+func TestOptional_MarshalJSON_arrayNulls(t *testing.T) {
+	in := []Optional[int]{Some(1), None[int](), Some(3)}
+
+	b, err := json.Marshal(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `[1,null,3]`; string(b) != want {
+		t.Errorf("got %s, want %s", b, want)
+	}
+
+	var out []Optional[int]
+	if err := json.Unmarshal(b, &out); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(out, in) {
+		t.Errorf("round-trip: got %v, want %v", out, in)
+	}
+}
+
+func TestOptional_MarshalJSON_emptySlice(t *testing.T) {
+	for _, tt := range []struct {
+		in   Optional[[]int]
+		want string
+	}{
+		{Some([]int{}), `[]`},
+		{None[[]int](), `null`},
+	} {
+		b, err := json.Marshal(tt.in)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(b) != tt.want {
+			t.Errorf("%v: got %s, want %s", tt.in, b, tt.want)
+		}
+
+		var out Optional[[]int]
+		if err := json.Unmarshal(b, &out); err != nil {
+			t.Fatal(err)
+		}
+		if out.IsSome() != tt.in.IsSome() || len(out.v) != 0 {
+			t.Errorf("%s: round-trip got %v, want %v", b, out, tt.in)
+		}
+	}
+}
+
+func TestMarshalCompactArray(t *testing.T) {
+	tests := []struct {
+		in   []Optional[int]
+		want string
+	}{
+		{[]Optional[int]{Some(1), None[int](), Some(3), None[int]()}, `[1,3]`},
+		{[]Optional[int]{None[int]()}, `[]`},
+		{nil, `[]`},
+	}
+
+	for _, tt := range tests {
+		b, err := MarshalCompactArray(tt.in)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(b) != tt.want {
+			t.Errorf("MarshalCompactArray(%v) = %s, want %s", tt.in, b, tt.want)
+		}
+	}
+}
+
+func TestOptional_SetJSON(t *testing.T) {
+	var opt Optional[[]int]
+
+	if err := opt.SetJSON([]byte(`[1,2,3]`)); err != nil {
+		t.Fatal(err)
+	}
+	if !opt.IsSome() || len(opt.Get()) != 3 {
+		t.Fatalf("got %v, want Some([1 2 3])", opt)
+	}
+	first := &opt.Get()[0]
+
+	if err := opt.SetJSON([]byte(`[4,5]`)); err != nil {
+		t.Fatal(err)
+	}
+	if got := opt.Get(); len(got) != 2 || got[0] != 4 || got[1] != 5 {
+		t.Fatalf("got %v, want Some([4 5])", opt)
+	}
+	if &opt.Get()[0] != first {
+		t.Error("backing array is not reused")
+	}
+
+	if err := opt.SetJSON([]byte(`null`)); err != nil {
+		t.Fatal(err)
+	}
+	if !opt.IsNone() {
+		t.Errorf("got %v, want None", opt)
+	}
+
+	if err := opt.SetJSON([]byte(`"abc"`)); err == nil {
+		t.Error("expected error on type mismatch")
+	}
+	if !opt.IsNone() {
+		t.Errorf("got %v, want None after error", opt)
+	}
+}
+
+type testMoney struct {
+	Cents    int64
+	Currency string
+}
+
+func TestOptional_Encode(t *testing.T) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetIndent("", "  ")
+	enc.SetEscapeHTML(false)
+
+	if err := Some(map[string]string{"a": "<b>"}).Encode(enc); err != nil {
+		t.Fatal(err)
+	}
+	if err := None[map[string]string]().Encode(enc); err != nil {
+		t.Fatal(err)
+	}
+
+	want := "{\n  \"a\": \"<b>\"\n}\nnull\n"
+	if buf.String() != want {
+		t.Errorf("got %q, want %q", buf.String(), want)
+	}
+}
+
+func TestRegisterMarshaler(t *testing.T) {
+	RegisterMarshaler(func(m testMoney) ([]byte, error) {
+		return json.Marshal(fmt.Sprintf("%d.%02d %s", m.Cents/100, m.Cents%100, m.Currency))
+	})
+	t.Cleanup(UnregisterMarshaler[testMoney])
+
+	v := struct {
+		Price    Optional[testMoney]
+		Discount Optional[testMoney]
+		Count    Optional[int]
+	}{
+		Price: Some(testMoney{Cents: 1250, Currency: "EUR"}),
+		Count: Some(2),
+	}
+
+	b, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := `{"Price":"12.50 EUR","Discount":null,"Count":2}`
+	if string(b) != want {
+		t.Errorf("got %s, want %s", b, want)
+	}
+}
+
+func TestSetTimeFormat(t *testing.T) {
+	SetTimeFormat(time.DateTime)
+	t.Cleanup(func() { SetTimeFormat("") })
+
+	ts := time.Date(2024, 5, 1, 12, 30, 0, 0, time.UTC)
+	v := struct {
+		At    Optional[time.Time]
+		Until Optional[time.Time]
+		Raw   time.Time
+	}{At: Some(ts), Raw: ts}
+
+	b, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"At":"2024-05-01 12:30:00","Until":null,"Raw":"2024-05-01T12:30:00Z"}`; string(b) != want {
+		t.Errorf("got %s, want %s", b, want)
+	}
+
+	SetTimeFormat("")
+	if b, _ := Some(ts).MarshalJSON(); string(b) != `"2024-05-01T12:30:00Z"` {
+		t.Errorf("after reset: got %s, want default encoding", b)
+	}
+}
+
+func FuzzOptional_MarshalJSON_string(f *testing.F) {
+	for _, s := range []string{
+		"",
+		"plain",
+		"line\nbreak",
+		"tab\there",
+		"nul\x00byte",
+		"\x1f\x7f",
+		`quote " and \ backslash`,
+		"<html>&amp;</html>",
+		"  ",
+		"invalid \xff utf-8",
+	} {
+		f.Add(s)
+	}
+
+	f.Fuzz(func(t *testing.T, s string) {
+		got, err := Some(s).MarshalJSON()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		want, _ := json.Marshal(s)
+		if string(got) != string(want) {
+			t.Errorf("MarshalJSON(%q) = %s, want %s", s, got, want)
+		}
+		if !json.Valid(got) {
+			t.Errorf("MarshalJSON(%q) produced invalid JSON %s", s, got)
+		}
+	})
+}
+
+func TestEncodeStructOmitNone(t *testing.T) {
+	type Address struct {
+		City   Optional[string] `json:"city"`
+		Street Optional[string] `json:"street"`
+		Zip    string           `json:"zip,omitempty"`
+	}
+
+	type Phone struct {
+		Kind   Optional[string]
+		Number string
+	}
+
+	type Meta struct {
+		Source Optional[string]
+	}
+
+	type Patch struct {
+		Meta
+		Name     Optional[string]          `json:"name"`
+		Age      Optional[int]             `json:"age"`
+		Address  Address                   `json:"address"`
+		Billing  *Address                  `json:"billing"`
+		Phones   []Phone                   `json:"phones"`
+		Parent   Optional[Address]         `json:"parent"`
+		Nickname Optional2[string]         `json:"nickname"`
+		Tags     map[string]Optional[bool] `json:"tags,omitempty"`
+		Secret   string                    `json:"-"`
+		internal Optional[int]
+	}
+
+	p := Patch{
+		Meta: Meta{Source: Some("api")},
+		Age:  Some(30),
+		Address: Address{
+			City: Some("Berlin"),
+		},
+		Billing: &Address{Zip: "10115"},
+		Phones: []Phone{
+			{Number: "123"},
+			{Kind: Some("mobile"), Number: "456"},
+		},
+		Parent:   Some(Address{Street: Some("Main")}),
+		Nickname: Some2(None[string]()),
+		Secret:   "s",
+		internal: Some(1),
+	}
+
+	b, err := EncodeStructOmitNone(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := `{"Source":"api","age":30,"address":{"city":"Berlin"},"billing":{"zip":"10115"},` +
+		`"phones":[{"Number":"123"},{"Kind":"mobile","Number":"456"}],"parent":{"street":"Main"},"nickname":null}`
+	if string(b) != want {
+		t.Errorf("got  %s\nwant %s", b, want)
+	}
+
+	b, err = EncodeStructOmitNone(struct {
+		A *Optional[int]
+		B *Optional[int]
+		C *Optional[int]
+	}{B: new(Optional[int]), C: &[]Optional[int]{Some(1)}[0]})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"A":null,"C":1}`; string(b) != want {
+		t.Errorf("pointers: got %s, want %s", b, want)
+	}
+}
+
+type testEmbeddedName string
+
+type testEmbeddedInner struct {
+	Inner Optional[int]
+	Dup   string
+}
+
+type testEmbeddedOther struct {
+	Dup    string
+	Tagged string `json:"Name"`
+}
+
+type testEmbeddedTagged struct {
+	Name string `json:"Name"`
+}
+
+func TestEncodeStructOmitNone_embedded(t *testing.T) {
+	type hidden struct {
+		Visible Optional[string]
+		secret  string
+	}
+	type Outer struct {
+		hidden
+		*testEmbeddedInner
+		testEmbeddedOther
+		testEmbeddedName
+		Missing *testEmbeddedTagged
+		Level   int
+	}
+
+	v := Outer{
+		hidden:            hidden{Visible: Some("v"), secret: "s"},
+		testEmbeddedInner: &testEmbeddedInner{Inner: Some(1), Dup: "a"},
+		testEmbeddedOther: testEmbeddedOther{Dup: "b", Tagged: "t"},
+		testEmbeddedName:  "n",
+		Level:             2,
+	}
+
+	got, err := EncodeStructOmitNone(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != string(want) {
+		t.Errorf("got  %s\nwant %s", got, want)
+	}
+
+	v.testEmbeddedInner = nil
+	v.Visible = None[string]()
+	got, err = EncodeStructOmitNone(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"Name":"t","Missing":null,"Level":2}`; string(got) != want {
+		t.Errorf("nil embedded pointer: got %s, want %s", got, want)
+	}
+}
+
+type testPtrMarshaler struct{ A int }
+
+func (*testPtrMarshaler) MarshalJSON() ([]byte, error) {
+	return []byte(`"custom"`), nil
+}
+
+func TestEncodeStructOmitNone_likeMarshal(t *testing.T) {
+	n := 7
+	tests := []struct {
+		name string
+		in   any
+	}{
+		{"pointer receiver marshaler in Optional", struct{ X Optional[testPtrMarshaler] }{Some(testPtrMarshaler{1})}},
+		{"string option", struct {
+			N  int     `json:",string"`
+			F  float64 `json:",string"`
+			B  bool    `json:",string"`
+			S  string  `json:",string"`
+			P  *int    `json:",string"`
+			NP *int    `json:",string"`
+		}{N: 5, F: 1.5, B: true, S: "a", P: &n}},
+	}
+
+	for _, tt := range tests {
+		got, err := EncodeStructOmitNone(tt.in)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		want, err := json.Marshal(tt.in)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if string(got) != string(want) {
+			t.Errorf("%s: got %s, want %s", tt.name, got, want)
+		}
+	}
+}
+
+func TestEncodeStructOmitNone_variants(t *testing.T) {
+	type circle struct{ R int }
+
+	tests := []struct {
+		name string
+		in   any
+		want string
+	}{
+		{"tagged", struct {
+			Shape Tagged[circle]
+			Empty Tagged[circle]
+		}{Shape: SomeTagged("c", circle{1})}, `{"Shape":{"type":"c","R":1}}`},
+		{"fixed", struct {
+			Price Fixed[float64]
+			Empty Fixed[float64]
+		}{Price: SomeFixed(12.5, 2)}, `{"Price":12.50}`},
+		{"strict", struct {
+			Name  Strict[string]
+			Empty Strict[string]
+		}{Name: Strict[string]{Some("a")}}, `{"Name":"a"}`},
+		{"empty string null", struct {
+			Name  EmptyStringNull[string]
+			Empty EmptyStringNull[string]
+		}{Name: EmptyStringNull[string]{Some("a")}}, `{"Name":"a","Empty":""}`},
+		{"none repr", struct {
+			Name  WithNoneRepr[string, testSentinelNull]
+			Empty WithNoneRepr[string, testSentinelNull]
+		}{Name: WithNoneRepr[string, testSentinelNull]{Some("a")}}, `{"Name":"a","Empty":{"__null":true}}`},
+	}
+
+	for _, tt := range tests {
+		got, err := EncodeStructOmitNone(tt.in)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if string(got) != tt.want {
+			t.Errorf("%s: got %s, want %s", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestNDJSON(t *testing.T) {
+	in := []Optional[string]{Some("a"), None[string](), Some("line\nbreak")}
+
+	var buf bytes.Buffer
+	if err := EncodeNDJSON(&buf, in); err != nil {
+		t.Fatal(err)
+	}
+
+	want := "\"a\"\nnull\n\"line\\nbreak\"\n"
+	if buf.String() != want {
+		t.Errorf("encoded %q, want %q", buf.String(), want)
+	}
+
+	out, err := DecodeNDJSON[string](&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(out, in) {
+		t.Errorf("decoded %v, want %v", out, in)
+	}
+
+	nums, err := DecodeNDJSON[int](strings.NewReader("1\n\nnull\n3"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []Optional[int]{Some(1), None[int](), Some(3)}; !reflect.DeepEqual(nums, want) {
+		t.Errorf("decoded %v, want %v", nums, want)
+	}
+
+	if _, err := DecodeNDJSON[int](strings.NewReader("1\n\"x\"\n")); err == nil {
+		t.Error("expected error on invalid line")
+	}
+}
+
+func TestOptional_MarshalJSON_concurrent(t *testing.T) {
+	type item struct {
+		Name Optional[string]
+		Tags Optional[[]string]
+	}
+
+	opt := Some(item{
+		Name: Some("box"),
+		Tags: Some([]string{"a", "b"}),
+	})
+	want, err := json.Marshal(opt)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var wg sync.WaitGroup
+	for range 16 {
+		wg.Go(func() {
+			for range 100 {
+				b, err := json.Marshal(opt)
+				if err != nil || !bytes.Equal(b, want) {
+					t.Errorf("got %s, %v; want %s", b, err, want)
+					return
+				}
+			}
+		})
+	}
+	wg.Wait()
+}
@@ -0,0 +1,13 @@
+module github.com/sevlyar/box/jsoniterbox
+
+go 1.25
+
+require (
+	github.com/json-iterator/go v1.1.12
+	github.com/modern-go/reflect2 v1.0.2
+	github.com/sevlyar/box v0.0.0
+)
+
+require github.com/modern-go/concurrent v0.0.0-20180228061459-e0a39a4cb421 // indirect
+
+replace github.com/sevlyar/box => ../
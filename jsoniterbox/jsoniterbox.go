@@ -0,0 +1,53 @@
+/*
+Package jsoniterbox integrates box types with [github.com/json-iterator/go].
+
+jsoniter calls MarshalJSON and UnmarshalJSON methods of the box types, but doesn't support
+the `omitzero` option, so [box.None] values can't be omitted from the encoding.
+[Extension] makes jsoniter treat zero values of the box types (i.e. [box.None]) as empty,
+so fields annotated with `json:",omitempty"` are omitted the same way
+encoding/json omits fields annotated with `json:",omitzero"`.
+
+	api := jsoniter.ConfigCompatibleWithStandardLibrary
+	api.RegisterExtension(&jsoniterbox.Extension{})
+*/
+package jsoniterbox
+
+import (
+	"reflect"
+	"unsafe"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/modern-go/reflect2"
+
+	"github.com/sevlyar/box"
+)
+
+var boxPkgPath = reflect.TypeFor[box.Optional[int]]().PkgPath()
+
+type zeroer interface {
+	IsZero() bool
+}
+
+// Extension is the jsoniter extension for the box types.
+type Extension struct {
+	jsoniter.DummyExtension
+}
+
+// DecorateEncoder reports zero values of the box types as empty for `omitempty` option.
+func (*Extension) DecorateEncoder(typ reflect2.Type, encoder jsoniter.ValEncoder) jsoniter.ValEncoder {
+	t := typ.Type1()
+	if t.PkgPath() != boxPkgPath || !t.Implements(reflect.TypeFor[zeroer]()) {
+		return encoder
+	}
+
+	return zeroEncoder{ValEncoder: encoder, typ: typ}
+}
+
+type zeroEncoder struct {
+	jsoniter.ValEncoder
+	typ reflect2.Type
+}
+
+func (enc zeroEncoder) IsEmpty(ptr unsafe.Pointer) bool {
+	return enc.typ.UnsafeIndirect(ptr).(zeroer).IsZero()
+}
@@ -0,0 +1,57 @@
+package jsoniterbox
+
+import (
+	"encoding/json"
+	"testing"
+
+	jsoniter "github.com/json-iterator/go"
+
+	"github.com/sevlyar/box"
+)
+
+func TestExtension(t *testing.T) {
+	api := jsoniter.Config{}.Froze()
+	api.RegisterExtension(&Extension{})
+
+	type User struct {
+		Name       string
+		MiddleName box.Optional[string] `json:",omitempty"`
+		Nickname   box.Optional[string] `json:",omitempty"`
+		Age        box.Optional[int]
+	}
+
+	u := User{
+		Name:     "John",
+		Nickname: box.Some("jd"),
+	}
+
+	b, err := api.Marshal(u)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := `{"Name":"John","Nickname":"jd","Age":null}`
+	if string(b) != want {
+		t.Errorf("got %s, want %s", b, want)
+	}
+
+	type StdUser struct {
+		Name       string
+		MiddleName box.Optional[string] `json:",omitzero"`
+		Nickname   box.Optional[string] `json:",omitzero"`
+		Age        box.Optional[int]
+	}
+
+	std, _ := json.Marshal(StdUser(u))
+	if string(b) != string(std) {
+		t.Errorf("jsoniter output %s differs from encoding/json %s", b, std)
+	}
+
+	var decoded User
+	if err := api.Unmarshal([]byte(`{"Name":"John","Nickname":"jd","Age":null}`), &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if decoded != u {
+		t.Errorf("decoded %+v, want %+v", decoded, u)
+	}
+}
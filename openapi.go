@@ -0,0 +1,64 @@
+package box
+
+import (
+	"encoding"
+	"encoding/json"
+	"reflect"
+	"time"
+)
+
+// OpenAPISchemaType returns the OpenAPI 3.1 schema types of the JSON presentation of [Optional]:
+// the type of T followed by "null", because [None] is presented as null.
+// Returns nil if the type of T can't be determined (e.g. T is an interface or implements
+// [json.Marshaler]).
+func (opt Optional[T]) OpenAPISchemaType() []string {
+	if typ := schemaType(reflect.TypeFor[T]()); typ != "" {
+		return []string{typ, "null"}
+	}
+
+	return nil
+}
+
+func schemaType(t reflect.Type) string {
+	// Box types implement encoding.TextMarshaler, but are presented in JSON by the nested value.
+	if t.Implements(reflectValuerType) && t.Kind() == reflect.Struct {
+		inner, _ := reflect.Zero(t).Interface().(reflectValuer).reflectValue()
+		return schemaType(inner.Type())
+	}
+
+	// The JSON presentation of a json.Marshaler is known only for time.Time.
+	if t == reflect.TypeFor[time.Time]() {
+		return "string"
+	}
+	if t.Implements(reflect.TypeFor[json.Marshaler]()) {
+		return ""
+	}
+	if t.Implements(reflect.TypeFor[encoding.TextMarshaler]()) {
+		return "string"
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return "boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return "integer"
+	case reflect.Float32, reflect.Float64:
+		return "number"
+	case reflect.String:
+		return "string"
+	case reflect.Slice:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return "string"
+		}
+		return "array"
+	case reflect.Array:
+		return "array"
+	case reflect.Map, reflect.Struct:
+		return "object"
+	case reflect.Pointer:
+		return schemaType(t.Elem())
+	}
+
+	return ""
+}
@@ -0,0 +1,41 @@
+package box
+
+import (
+	"encoding/json"
+	"math/big"
+	"net"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestOptional_OpenAPISchemaType(t *testing.T) {
+	tests := []struct {
+		name string
+		got  []string
+		want []string
+	}{
+		{"string", None[string]().OpenAPISchemaType(), []string{"string", "null"}},
+		{"int", None[int64]().OpenAPISchemaType(), []string{"integer", "null"}},
+		{"float", None[float32]().OpenAPISchemaType(), []string{"number", "null"}},
+		{"bool", None[bool]().OpenAPISchemaType(), []string{"boolean", "null"}},
+		{"bytes", None[[]byte]().OpenAPISchemaType(), []string{"string", "null"}},
+		{"slice", None[[]int]().OpenAPISchemaType(), []string{"array", "null"}},
+		{"struct", None[struct{ A int }]().OpenAPISchemaType(), []string{"object", "null"}},
+		{"time", None[time.Time]().OpenAPISchemaType(), []string{"string", "null"}},
+		{"pointer", None[*int]().OpenAPISchemaType(), []string{"integer", "null"}},
+		{"interface", None[any]().OpenAPISchemaType(), nil},
+		{"json marshaler", None[*big.Int]().OpenAPISchemaType(), nil},
+		{"raw message", None[json.RawMessage]().OpenAPISchemaType(), nil},
+		{"text marshaler", None[net.IP]().OpenAPISchemaType(), []string{"string", "null"}},
+		{"nested", None[Optional[int]]().OpenAPISchemaType(), []string{"integer", "null"}},
+		{"optional2", None2[string]().OpenAPISchemaType(), []string{"string", "null"}},
+		{"variant", None[Fixed[float64]]().OpenAPISchemaType(), []string{"number", "null"}},
+	}
+
+	for _, tt := range tests {
+		if !reflect.DeepEqual(tt.got, tt.want) {
+			t.Errorf("%s: got %v, want %v", tt.name, tt.got, tt.want)
+		}
+	}
+}
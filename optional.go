@@ -5,18 +5,30 @@ import (
 	"database/sql"
 	"database/sql/driver"
 	"encoding/json"
+	"reflect"
 )
 
 // Optional represents optional value of type T.
 // Optional value must be [Some] (i.e. having a value) or [None] (i.e. doesn't have a value).
 // Optional is a comparable value-type. Don't recommend to use with big or complex types.
 // Zero value of Optional is [None].
+// Optional stores the value inline, so a recursive type must refer to itself through a pointer or a slice,
+// e.g. Optional[*Node] or []Optional[Node].
 //
 // Optional implements [sql.Scanner] and [driver.Valuer] interfaces.
 // To database value conversion works, T should be one of the types accepted by [driver.Value]
 // or implements the interfaces. [None] in database presented as NULL.
 //
 // Optional implements (un)marshalling from/to JSON. [None] value presented as null.
+// Optional[struct{}] can be used as a presence flag: Some(struct{}{}) is presented as {}.
+// Some of an empty slice is presented as [], but Some of a nil slice is presented as null
+// like by [json.Marshal], so use an empty non-nil slice to keep it distinct from [None].
+//
+// Optional implements [encoding.TextMarshaler] and [encoding.TextUnmarshaler] interfaces.
+// [None] value presented as empty text, see [Optional.MarshalText].
+//
+// Optional implements [encoding/gob.GobEncoder] and [encoding/gob.GobDecoder] interfaces.
+// [None] fields are not transmitted by gob, see [Optional.GobEncode] for the limitations.
 type Optional[T any] struct {
 	some bool
 	v    T
@@ -35,6 +47,15 @@ func None[T any]() Optional[T] {
 	return Optional[T]{}
 }
 
+// FromPtr returns [Optional] with the pointed value, or [None] if the pointer is nil.
+func FromPtr[T any](p *T) Optional[T] {
+	if p == nil {
+		return None[T]()
+	}
+
+	return Some(*p)
+}
+
 // IsSome returns true if the [Optional] value is [Some].
 func (opt Optional[T]) IsSome() bool {
 	return opt.some
@@ -55,6 +76,98 @@ func (opt Optional[T]) Get() T {
 	return opt.v
 }
 
+// Take returns underlying value and true if [Optional] is [Some],
+// otherwise the zero value of T and false.
+func (opt Optional[T]) Take() (T, bool) {
+	return opt.v, opt.some
+}
+
+// GetOr returns underlying value if [Optional] is [Some], otherwise def.
+func (opt Optional[T]) GetOr(def T) T {
+	if !opt.some {
+		return def
+	}
+
+	return opt.v
+}
+
+// GetOrElse returns underlying value if [Optional] is [Some], otherwise the result of f.
+// f is called only if [Optional] is [None].
+func (opt Optional[T]) GetOrElse(f func() T) T {
+	if !opt.some {
+		return f()
+	}
+
+	return opt.v
+}
+
+// GetOrZero returns underlying value if [Optional] is [Some], otherwise the zero value of T.
+func (opt Optional[T]) GetOrZero() T {
+	return opt.v
+}
+
+// Ptr returns a pointer to a copy of the underlying value if [Optional] is [Some], otherwise nil.
+// Modification of the pointed value doesn't affect the [Optional].
+func (opt Optional[T]) Ptr() *T {
+	if !opt.some {
+		return nil
+	}
+
+	return &opt.v
+}
+
+// OrZeroPtr is the same as [Optional.Ptr], named to contrast with [Optional.AlwaysPtr].
+func (opt Optional[T]) OrZeroPtr() *T {
+	return opt.Ptr()
+}
+
+// AlwaysPtr returns a pointer to a copy of the underlying value if [Optional] is [Some],
+// otherwise a pointer to the zero value of T. The result is never nil.
+func (opt Optional[T]) AlwaysPtr() *T {
+	return &opt.v
+}
+
+// Reset sets the [Optional] to [None]. The stored value is zeroed,
+// so the memory it refers to can be released by GC.
+func (opt *Optional[T]) Reset() {
+	*opt = None[T]()
+}
+
+// Set sets the value and marks the [Optional] as [Some].
+func (opt *Optional[T]) Set(val T) {
+	*opt = Some(val)
+}
+
+// Clear is the same as [Optional.Reset].
+func (opt *Optional[T]) Clear() {
+	opt.Reset()
+}
+
+// Validate calls Validate method of the underlying value if [Optional] is [Some]
+// and T (or *T) implements it. [None] and Some of a nil pointer are always valid.
+func (opt Optional[T]) Validate() error {
+	if !opt.some {
+		return nil
+	}
+
+	if rv := reflect.ValueOf(opt.v); rv.Kind() == reflect.Pointer && rv.IsNil() {
+		return nil
+	}
+
+	if v, ok := any(opt.v).(validator); ok {
+		return v.Validate()
+	}
+	if v, ok := any(&opt.v).(validator); ok {
+		return v.Validate()
+	}
+
+	return nil
+}
+
+type validator interface {
+	Validate() error
+}
+
 var (
 	_ driver.Valuer = Optional[any]{}
 	_ sql.Scanner   = (*Optional[any])(nil)
@@ -64,19 +177,28 @@ var (
 )
 
 func (opt Optional[T]) Value() (driver.Value, error) {
-	n := sql.Null[T]{
-		Valid: opt.some,
-		V:     opt.v,
-	}
-
-	return n.Value()
+	return opt.ToSQLNull().Value()
 }
 
+// Scan implements [sql.Scanner]. NULL is scanned as [None].
+// A source implementing [driver.Valuer] (e.g. [sql.NullString]) is unwrapped first,
+// unless it is of type T itself. Integer sources are converted to unsigned T
+// when they are non-negative and fit into T, otherwise an error is returned.
 func (opt *Optional[T]) Scan(src any) error {
 	var n sql.Null[T]
 
-	if err := n.Scan(src); err != nil {
-		return err
+	val := src
+	if v, ok := src.(driver.Valuer); ok {
+		if _, isT := src.(T); !isT {
+			var err error
+			if val, err = v.Value(); err != nil {
+				return opt.scanError(src, err)
+			}
+		}
+	}
+
+	if err := n.Scan(val); err != nil {
+		return opt.scanError(src, err)
 	}
 
 	opt.some = n.Valid
@@ -85,10 +207,26 @@ func (opt *Optional[T]) Scan(src any) error {
 	return nil
 }
 
+// scanError resets the [Optional] to [None] and returns [ScanError] for the failed Scan.
+func (opt *Optional[T]) scanError(src any, err error) error {
+	*opt = None[T]()
+
+	return &ScanError{
+		Src:  reflect.TypeOf(src),
+		Dest: reflect.TypeFor[Optional[T]](),
+		Err:  err,
+	}
+}
+
 func (opt Optional[T]) IsZero() bool {
 	return !opt.some
 }
 
+// reflectValue returns the underlying value for reflection-based encoders and whether it is presented.
+func (opt Optional[T]) reflectValue() (reflect.Value, bool) {
+	return reflect.ValueOf(&opt.v).Elem(), opt.some
+}
+
 var nullStrBytes = []byte("null")
 
 func (opt Optional[T]) MarshalJSON() ([]byte, error) {
@@ -96,18 +234,27 @@ func (opt Optional[T]) MarshalJSON() ([]byte, error) {
 		return nullStrBytes, nil
 	}
 
+	if f, ok := registeredMarshaler[T](); ok {
+		return f(opt.v)
+	}
+
 	return json.Marshal(opt.v)
 }
 
+// UnmarshalJSON decodes JSON null as [None] and any other value as [Some].
+// In case of error the [Optional] is reset to [None] and the error is returned.
 func (opt *Optional[T]) UnmarshalJSON(data []byte) error {
 	if bytes.Equal(data, nullStrBytes) {
 		*opt = None[T]()
 		return nil
 	}
 
-	err := json.Unmarshal(data, &opt.v)
-	opt.some = err == nil
+	if err := json.Unmarshal(data, &opt.v); err != nil {
+		*opt = None[T]()
+		return err
+	}
 
+	opt.some = true
 	return nil
 }
 
@@ -141,8 +288,11 @@ func (opt2 Optional2[T]) MarshalJSON() ([]byte, error) {
 }
 
 func (opt2 *Optional2[T]) UnmarshalJSON(data []byte) error {
-	err := opt2.v.UnmarshalJSON(data)
-	opt2.some = err == nil
+	if err := opt2.v.UnmarshalJSON(data); err != nil {
+		*opt2 = None2[T]()
+		return err
+	}
 
+	opt2.some = true
 	return nil
 }
@@ -2,7 +2,13 @@ package box
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
+	"reflect"
+	"runtime"
+	"strings"
+	"testing"
+	"weak"
 )
 
 // Zero value of Optional type is None.
@@ -105,3 +111,461 @@ func ExampleOptional2_use() {
 	// Output:
 	// true true true
 }
+
+func TestOptional_recursiveJSON(t *testing.T) {
+	type Node struct {
+		Value    int
+		Left     Optional[*Node] `json:",omitzero"`
+		Right    Optional[*Node] `json:",omitzero"`
+		Children []Optional[Node]
+	}
+
+	tree := Node{
+		Value: 1,
+		Left: Some(&Node{
+			Value: 2,
+			Right: Some(&Node{Value: 4}),
+		}),
+		Children: []Optional[Node]{
+			Some(Node{Value: 3}),
+			None[Node](),
+		},
+	}
+
+	b, err := json.Marshal(tree)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := `{"Value":1,"Left":{"Value":2,"Right":{"Value":4,"Children":null},"Children":null},` +
+		`"Children":[{"Value":3,"Children":null},null]}`
+	if string(b) != want {
+		t.Errorf("got %s, want %s", b, want)
+	}
+
+	var decoded Node
+	if err := json.Unmarshal(b, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(decoded, tree) {
+		t.Errorf("round-trip mismatch: got %+v, want %+v", decoded, tree)
+	}
+}
+
+// Optional[struct{}] can be used as a presence flag,
+// Some is presented in JSON as an empty object.
+func ExampleOptional_flag() {
+	type Features struct {
+		Beta   Optional[struct{}] `json:",omitzero"`
+		Legacy Optional[struct{}] `json:",omitzero"`
+	}
+
+	b, _ := json.Marshal(Features{
+		Beta: Some(struct{}{}),
+	})
+	fmt.Println(string(b))
+
+	var f Features
+	_ = json.Unmarshal([]byte(`{"Legacy":{}}`), &f)
+	fmt.Println(f.Beta.IsSome(), f.Legacy.IsSome())
+	// Output:
+	// {"Beta":{}}
+	// false true
+}
+
+func TestOptional_Reset(t *testing.T) {
+	buf := Some(make([]byte, 1024))
+	buf.Reset()
+	if !buf.IsNone() {
+		t.Error("expected None after Reset")
+	}
+	if buf.v != nil {
+		t.Error("stored slice is retained after Reset")
+	}
+
+	type big struct{ data [1 << 10]byte }
+	ptr := Some(&big{})
+	ptr.Reset()
+	if ptr.v != nil {
+		t.Error("stored pointer is retained after Reset")
+	}
+	if ptr != None[*big]() {
+		t.Error("reset value is not equal to None")
+	}
+}
+
+type testEmail string
+
+func (e testEmail) Validate() error {
+	if !strings.Contains(string(e), "@") {
+		return errors.New("invalid email")
+	}
+	return nil
+}
+
+func TestOptional_Validate(t *testing.T) {
+	if err := Some[testEmail]("john@example.com").Validate(); err != nil {
+		t.Errorf("valid Some: unexpected error: %v", err)
+	}
+	if err := Some[testEmail]("john").Validate(); err == nil {
+		t.Error("invalid Some: expected error")
+	}
+	if err := None[testEmail]().Validate(); err != nil {
+		t.Errorf("None: unexpected error: %v", err)
+	}
+	if err := Some(42).Validate(); err != nil {
+		t.Errorf("non-validatable Some: unexpected error: %v", err)
+	}
+
+	email := testEmail("john")
+	if err := Some(&email).Validate(); err == nil {
+		t.Error("pointer to value receiver: expected error")
+	}
+	if err := Some(&testForm{}).Validate(); err == nil {
+		t.Error("pointer receiver: expected error")
+	}
+	if err := Some(testForm{}).Validate(); err == nil {
+		t.Error("pointer receiver on value: expected error")
+	}
+	if err := Some(&testForm{Name: "a"}).Validate(); err != nil {
+		t.Errorf("valid pointer: unexpected error: %v", err)
+	}
+	if err := Some[*testForm](nil).Validate(); err != nil {
+		t.Errorf("nil pointer: unexpected error: %v", err)
+	}
+}
+
+type testForm struct {
+	Name string
+}
+
+func (f *testForm) Validate() error {
+	if f.Name == "" {
+		return errors.New("name is required")
+	}
+	return nil
+}
+
+// Optional[[]Optional[T]] distinguishes a null array from an array containing nulls,
+// e.g. for Postgres arrays with nullable elements.
+func ExampleOptional_nullableElements() {
+	arrays := []Optional[[]Optional[int]]{
+		None[[]Optional[int]](),
+		Some([]Optional[int]{}),
+		Some([]Optional[int]{None[int](), Some(1), Some(2)}),
+	}
+
+	for _, arr := range arrays {
+		b, _ := json.Marshal(arr)
+		fmt.Println(string(b))
+	}
+
+	var decoded Optional[[]Optional[int]]
+	_ = json.Unmarshal([]byte(`[null,1]`), &decoded)
+	fmt.Println(decoded.IsSome(), decoded.Get()[0].IsNone(), decoded.Get()[1].Get())
+	// Output:
+	// null
+	// []
+	// [null,1,2]
+	// true true 1
+}
+
+func TestOptional_OrZeroPtr(t *testing.T) {
+	if p := None[int]().OrZeroPtr(); p != nil {
+		t.Errorf("None: got %v, want nil", *p)
+	}
+
+	opt := Some(5)
+	p := opt.OrZeroPtr()
+	if p == nil || *p != 5 {
+		t.Fatalf("Some: got %v, want pointer to 5", p)
+	}
+	*p = 6
+	if opt.Get() != 5 {
+		t.Error("Some: pointer refers to the internal value")
+	}
+}
+
+func TestOptional_AlwaysPtr(t *testing.T) {
+	p := None[int]().AlwaysPtr()
+	if p == nil || *p != 0 {
+		t.Errorf("None: got %v, want pointer to zero", p)
+	}
+
+	if p := Some(5).AlwaysPtr(); p == nil || *p != 5 {
+		t.Errorf("Some: got %v, want pointer to 5", p)
+	}
+}
+
+func TestOptional_MarshalJSON_elementTags(t *testing.T) {
+	type Profile struct {
+		DisplayName string `json:"display_name"`
+		Bio         string `json:"bio,omitempty"`
+		Internal    string `json:"-"`
+	}
+
+	b, err := json.Marshal(Some(Profile{DisplayName: "John", Internal: "x"}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"display_name":"John"}`; string(b) != want {
+		t.Errorf("got %s, want %s", b, want)
+	}
+
+	var opt Optional[Profile]
+	if err := json.Unmarshal([]byte(`{"display_name":"Jane","bio":"hi"}`), &opt); err != nil {
+		t.Fatal(err)
+	}
+	if want := Some(Profile{DisplayName: "Jane", Bio: "hi"}); opt != want {
+		t.Errorf("got %+v, want %+v", opt, want)
+	}
+}
+
+func TestOptional_UnmarshalJSON_errors(t *testing.T) {
+	type User struct {
+		Age Optional[int]
+	}
+
+	tests := []struct {
+		name    string
+		input   string
+		want    Optional[int]
+		wantErr bool
+	}{
+		{"valid", `{"Age": 42}`, Some(42), false},
+		{"null", `{"Age": null}`, None[int](), false},
+		{"absent", `{}`, None[int](), false},
+		{"type mismatch", `{"Age": "abc"}`, None[int](), true},
+		{"garbage", `{"Age": 4x}`, None[int](), true},
+	}
+
+	for _, tt := range tests {
+		var u User
+		err := json.Unmarshal([]byte(tt.input), &u)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s: error = %v, wantErr %v", tt.name, err, tt.wantErr)
+		}
+		if u.Age != tt.want {
+			t.Errorf("%s: got %v, want %v", tt.name, u.Age, tt.want)
+		}
+	}
+
+	opt := Some(1)
+	if err := opt.UnmarshalJSON([]byte(`"abc"`)); err == nil {
+		t.Error("direct call: expected error on type mismatch")
+	}
+	if opt != None[int]() {
+		t.Errorf("direct call: got %v, want None after error", opt)
+	}
+}
+
+func TestOptional2_UnmarshalJSON_errors(t *testing.T) {
+	type Form struct {
+		Age Optional2[int]
+	}
+
+	tests := []struct {
+		name    string
+		input   string
+		want    Optional2[int]
+		wantErr bool
+	}{
+		{"valid", `{"Age": 42}`, Some2(Some(42)), false},
+		{"null", `{"Age": null}`, Some2(None[int]()), false},
+		{"absent", `{}`, None2[int](), false},
+		{"type mismatch", `{"Age": "abc"}`, None2[int](), true},
+		{"garbage", `{"Age": 4x}`, None2[int](), true},
+	}
+
+	for _, tt := range tests {
+		var f Form
+		err := json.Unmarshal([]byte(tt.input), &f)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s: error = %v, wantErr %v", tt.name, err, tt.wantErr)
+		}
+		if f.Age != tt.want {
+			t.Errorf("%s: got %v, want %v", tt.name, f.Age, tt.want)
+		}
+	}
+
+	opt2 := Some2(Some(1))
+	if err := opt2.UnmarshalJSON([]byte(`"abc"`)); err == nil {
+		t.Error("direct call: expected error on type mismatch")
+	}
+	if opt2 != None2[int]() {
+		t.Errorf("direct call: got %v, want None2 after error", opt2)
+	}
+}
+
+func TestOptional_GetOr(t *testing.T) {
+	if got := Some(1).GetOr(2); got != 1 {
+		t.Errorf("Some: got %v, want 1", got)
+	}
+	if got := None[int]().GetOr(2); got != 2 {
+		t.Errorf("None: got %v, want 2", got)
+	}
+
+	a, b := new(int), new(int)
+	if got := Some(a).GetOr(b); got != a {
+		t.Error("Some pointer: got default pointer")
+	}
+	if got := None[*int]().GetOr(b); got != b {
+		t.Error("None pointer: got wrong pointer")
+	}
+	if got := Some[*int](nil).GetOr(b); got != nil {
+		t.Error("Some(nil): got default pointer, want nil")
+	}
+}
+
+func TestOptional_GetOrZero(t *testing.T) {
+	type point struct{ X, Y int }
+
+	if got := Some("a").GetOrZero(); got != "a" {
+		t.Errorf("Some string: got %q, want a", got)
+	}
+	if got := None[string]().GetOrZero(); got != "" {
+		t.Errorf("None string: got %q, want empty", got)
+	}
+	if got := Some(point{1, 2}).GetOrZero(); got != (point{1, 2}) {
+		t.Errorf("Some struct: got %v, want {1 2}", got)
+	}
+	if got := None[point]().GetOrZero(); got != (point{}) {
+		t.Errorf("None struct: got %v, want zero", got)
+	}
+	if got := None[*point]().GetOrZero(); got != nil {
+		t.Errorf("None pointer: got %v, want nil", got)
+	}
+
+	var opt Optional[int]
+	_ = opt.UnmarshalJSON([]byte(`"x"`))
+	if got := opt.GetOrZero(); got != 0 {
+		t.Errorf("None after failed decoding: got %v, want 0", got)
+	}
+}
+
+func TestOptional_GetOrElse(t *testing.T) {
+	calls := 0
+	def := func() int {
+		calls++
+		return 2
+	}
+
+	if got := Some(1).GetOrElse(def); got != 1 {
+		t.Errorf("Some: got %v, want 1", got)
+	}
+	if calls != 0 {
+		t.Errorf("Some: f called %d times, want 0", calls)
+	}
+
+	if got := None[int]().GetOrElse(def); got != 2 {
+		t.Errorf("None: got %v, want 2", got)
+	}
+	if calls != 1 {
+		t.Errorf("None: f called %d times, want 1", calls)
+	}
+}
+
+func TestOptional_Take(t *testing.T) {
+	if v, ok := Some("a").Take(); v != "a" || !ok {
+		t.Errorf("Some: got (%q, %v), want (a, true)", v, ok)
+	}
+	if v, ok := None[string]().Take(); v != "" || ok {
+		t.Errorf("None: got (%q, %v), want (\"\", false)", v, ok)
+	}
+	if v, ok := None[*int]().Take(); v != nil || ok {
+		t.Errorf("None pointer: got (%v, %v), want (nil, false)", v, ok)
+	}
+}
+
+func TestOptional_Ptr(t *testing.T) {
+	if p := None[int]().Ptr(); p != nil {
+		t.Errorf("None: got %v, want nil", *p)
+	}
+
+	opt := Some(5)
+	p := opt.Ptr()
+	if p == nil || *p != 5 {
+		t.Fatalf("Some: got %v, want pointer to 5", p)
+	}
+	*p = 6
+	if opt.Get() != 5 {
+		t.Error("Some: modification via pointer affected the Optional")
+	}
+	if p2 := opt.Ptr(); p2 == p {
+		t.Error("Some: pointers to the same copy returned twice")
+	}
+}
+
+func TestFromPtr(t *testing.T) {
+	if got := FromPtr[int](nil); got != None[int]() {
+		t.Errorf("nil: got %v, want None", got)
+	}
+
+	v := 5
+	opt := FromPtr(&v)
+	if opt != Some(5) {
+		t.Errorf("non-nil: got %v, want Some(5)", opt)
+	}
+	v = 6
+	if opt.Get() != 5 {
+		t.Error("non-nil: modification of the source affected the Optional")
+	}
+}
+
+func TestOptional_SetClear(t *testing.T) {
+	var opt Optional[int]
+	opt.Set(5)
+	if opt != Some(5) {
+		t.Errorf("Set: got %v, want Some(5)", opt)
+	}
+	opt.Clear()
+	if opt != None[int]() {
+		t.Errorf("Clear: got %v, want None", opt)
+	}
+
+	type big struct{ data [1 << 10]byte }
+	ptr := Some(&big{})
+	w := weak.Make(ptr.v)
+	ptr.Clear()
+	if ptr.v != nil {
+		t.Error("stored pointer is retained after Clear")
+	}
+	runtime.GC()
+	if w.Value() != nil {
+		t.Error("cleared value is still reachable after GC")
+	}
+}
+
+func TestOptional2_JSON_threeStates(t *testing.T) {
+	type patch struct {
+		Name Optional2[string] `json:"name,omitzero"`
+	}
+
+	tests := []struct {
+		name string
+		val  Optional2[string]
+		json string
+	}{
+		{"absent", None2[string](), `{}`},
+		{"null", Some2(None[string]()), `{"name":null}`},
+		{"value", Some2(Some("a")), `{"name":"a"}`},
+	}
+
+	for _, tt := range tests {
+		b, err := json.Marshal(patch{tt.val})
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if string(b) != tt.json {
+			t.Errorf("%s: marshal got %s, want %s", tt.name, b, tt.json)
+		}
+
+		var p patch
+		if err := json.Unmarshal([]byte(tt.json), &p); err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if p.Name != tt.val {
+			t.Errorf("%s: unmarshal got %v, want %v", tt.name, p.Name, tt.val)
+		}
+	}
+}
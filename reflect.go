@@ -0,0 +1,307 @@
+package box
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"text/tabwriter"
+)
+
+// reflectValuer is implemented by the box types.
+type reflectValuer interface {
+	reflectValue() (reflect.Value, bool)
+}
+
+var reflectValuerType = reflect.TypeFor[reflectValuer]()
+
+// isNone reports whether the value is one of the box types holding [None].
+func isNone(rv reflect.Value) bool {
+	if !rv.Type().Implements(reflectValuerType) || rv.Kind() == reflect.Pointer && rv.IsNil() {
+		return false
+	}
+
+	_, some := rv.Interface().(reflectValuer).reflectValue()
+	return !some
+}
+
+// PresenceStats counts [Some] and [None] fields of the box types in the struct v points to
+// (or the struct v is). Exported fields of nested structs, pointers to structs and
+// values of [Some] fields are inspected recursively.
+// Panics if v is not a struct or a pointer to struct.
+func PresenceStats(v any) (present, absent int) {
+	rv := reflect.Indirect(reflect.ValueOf(v))
+	if rv.Kind() != reflect.Struct {
+		panic(fmt.Sprintf("PresenceStats: struct expected, got %T", v))
+	}
+
+	presenceStats(rv, &present, &absent)
+	return present, absent
+}
+
+func presenceStats(rv reflect.Value, present, absent *int) {
+	t := rv.Type()
+	for i := range t.NumField() {
+		if !t.Field(i).IsExported() {
+			continue
+		}
+
+		fv := rv.Field(i)
+		if fv.Kind() == reflect.Pointer {
+			if fv.IsNil() {
+				continue
+			}
+			fv = fv.Elem()
+		}
+
+		if fv.Type().Implements(reflectValuerType) {
+			inner, some := fv.Interface().(reflectValuer).reflectValue()
+			if !some {
+				*absent++
+				continue
+			}
+
+			*present++
+			fv = reflect.Indirect(inner)
+		}
+
+		if fv.Kind() == reflect.Struct {
+			presenceStats(fv, present, absent)
+		}
+	}
+}
+
+type textParser interface {
+	parseText(s string) error
+}
+
+var textParserType = reflect.TypeFor[textParser]()
+
+// ApplyDefaults sets [None] fields of the struct v points to with the default values
+// from `box:"default=..."` struct tags. The default value is parsed with the same rules as
+// [Optional.ScanText] uses. Exported fields of nested structs are processed recursively.
+//
+//	type Config struct {
+//		Host    Optional[string] `box:"default=localhost"`
+//		Port    Optional[int]    `box:"default=8080"`
+//		Verbose Optional[bool]   `box:"default=false"`
+//	}
+func ApplyDefaults(v any) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("ApplyDefaults: non-nil pointer to struct expected, got %T", v)
+	}
+
+	return applyDefaults(rv.Elem())
+}
+
+func applyDefaults(rv reflect.Value) error {
+	t := rv.Type()
+	for i := range t.NumField() {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+
+		fv := rv.Field(i)
+		if def, ok := strings.CutPrefix(f.Tag.Get("box"), "default="); ok {
+			if !reflect.PointerTo(f.Type).Implements(textParserType) {
+				return fmt.Errorf("ApplyDefaults: field %s of type %s doesn't support defaults", f.Name, f.Type)
+			}
+			if isNone(fv) {
+				if err := fv.Addr().Interface().(textParser).parseText(def); err != nil {
+					return fmt.Errorf("ApplyDefaults: field %s: %w", f.Name, err)
+				}
+			}
+			continue
+		}
+
+		if fv.Kind() == reflect.Struct && !f.Type.Implements(reflectValuerType) {
+			if err := applyDefaults(fv); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// Dump writes a tab-aligned table of the box type fields of the struct v points to (or the struct v is):
+// field name, presence and the value formatted with %v for [Some] fields.
+// Fields of nested structs are written with dotted names, pointers are followed and
+// nil pointers to box types are written with nil state. It is intended for debugging.
+// Panics if v is not a struct or a pointer to struct.
+func Dump(w io.Writer, v any) {
+	rv := reflect.Indirect(reflect.ValueOf(v))
+	if rv.Kind() != reflect.Struct {
+		panic(fmt.Sprintf("Dump: struct expected, got %T", v))
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "FIELD\tSTATE\tVALUE")
+	dump(tw, "", rv)
+	_ = tw.Flush()
+}
+
+func dump(w io.Writer, prefix string, rv reflect.Value) {
+	t := rv.Type()
+	for i := range t.NumField() {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+
+		fv := rv.Field(i)
+		if fv.Kind() == reflect.Pointer {
+			if fv.IsNil() {
+				if f.Type.Elem().Implements(reflectValuerType) {
+					fmt.Fprintf(w, "%s%s\tnil\t\n", prefix, f.Name)
+				}
+				continue
+			}
+			fv = fv.Elem()
+		}
+
+		switch {
+		case fv.Type().Implements(reflectValuerType):
+			inner, some := fv.Interface().(reflectValuer).reflectValue()
+			if some {
+				fmt.Fprintf(w, "%s%s\tSome\t%s\n", prefix, f.Name, dumpValue(inner))
+			} else {
+				fmt.Fprintf(w, "%s%s\tNone\t\n", prefix, f.Name)
+			}
+		case fv.Kind() == reflect.Struct:
+			dump(w, prefix+f.Name+".", fv)
+		}
+	}
+}
+
+// dumpValue formats the value with %v, nested box type values are formatted as None or Some(value).
+func dumpValue(rv reflect.Value) string {
+	if !rv.Type().Implements(reflectValuerType) {
+		return fmt.Sprint(rv)
+	}
+
+	inner, some := rv.Interface().(reflectValuer).reflectValue()
+	if !some {
+		return "None"
+	}
+
+	return "Some(" + dumpValue(inner) + ")"
+}
+
+// DeepClone returns a deep copy of the [Optional]: the underlying value of [Some] is copied
+// recursively through pointers, slices, arrays, maps, interfaces and exported struct fields,
+// so the clone shares no mutable state with the original. Cyclic and shared pointers are
+// preserved in the copy. Map keys, unexported struct fields, channels and functions
+// are copied shallowly.
+//
+// DeepClone uses reflection, which is much slower than copying by a hand-written function,
+// so it isn't recommended for hot paths.
+func DeepClone[T any](opt Optional[T]) Optional[T] {
+	return opt.deepClone(make(map[visitKey]reflect.Value)).Interface().(Optional[T])
+}
+
+// deepCloner is implemented by [Optional] to be deep-copied despite its unexported fields.
+type deepCloner interface {
+	deepClone(visited map[visitKey]reflect.Value) reflect.Value
+	optionalType() reflect.Type
+}
+
+var deepClonerType = reflect.TypeFor[deepCloner]()
+
+// optionalType returns the type of the [Optional]. It differs from the dynamic type
+// of a value of a type embedding the [Optional], which gets the method promoted.
+func (opt Optional[T]) optionalType() reflect.Type {
+	return reflect.TypeFor[Optional[T]]()
+}
+
+// deepClone returns the deep copy of the [Optional], visited is shared with the enclosing copy
+// to preserve cyclic and shared pointers across nested box values.
+func (opt Optional[T]) deepClone(visited map[visitKey]reflect.Value) reflect.Value {
+	if !opt.some {
+		return reflect.ValueOf(opt)
+	}
+
+	var v T
+	reflect.ValueOf(&v).Elem().Set(deepCopy(reflect.ValueOf(&opt.v).Elem(), visited))
+
+	return reflect.ValueOf(Some(v))
+}
+
+// visitKey identifies a pointer already copied by deepCopy.
+type visitKey struct {
+	ptr uintptr
+	typ reflect.Type
+}
+
+func deepCopy(src reflect.Value, visited map[visitKey]reflect.Value) reflect.Value {
+	if src.Kind() == reflect.Struct && src.Type().Implements(deepClonerType) {
+		// Types embedding Optional get the promoted methods, they are copied field by field.
+		if c := src.Interface().(deepCloner); c.optionalType() == src.Type() {
+			return c.deepClone(visited)
+		}
+	}
+
+	switch src.Kind() {
+	case reflect.Pointer:
+		if src.IsNil() {
+			return src
+		}
+		key := visitKey{src.Pointer(), src.Type()}
+		if p, ok := visited[key]; ok {
+			return p
+		}
+		p := reflect.New(src.Type().Elem())
+		visited[key] = p
+		p.Elem().Set(deepCopy(src.Elem(), visited))
+		return p
+
+	case reflect.Interface:
+		if src.IsNil() {
+			return src
+		}
+		dst := reflect.New(src.Type()).Elem()
+		dst.Set(deepCopy(src.Elem(), visited))
+		return dst
+
+	case reflect.Slice:
+		if src.IsNil() {
+			return src
+		}
+		dst := reflect.MakeSlice(src.Type(), src.Len(), src.Cap())
+		for i := range src.Len() {
+			dst.Index(i).Set(deepCopy(src.Index(i), visited))
+		}
+		return dst
+
+	case reflect.Array:
+		dst := reflect.New(src.Type()).Elem()
+		for i := range src.Len() {
+			dst.Index(i).Set(deepCopy(src.Index(i), visited))
+		}
+		return dst
+
+	case reflect.Map:
+		if src.IsNil() {
+			return src
+		}
+		dst := reflect.MakeMapWithSize(src.Type(), src.Len())
+		for it := src.MapRange(); it.Next(); {
+			dst.SetMapIndex(it.Key(), deepCopy(it.Value(), visited))
+		}
+		return dst
+
+	case reflect.Struct:
+		dst := reflect.New(src.Type()).Elem()
+		dst.Set(src)
+		for i := range src.NumField() {
+			if src.Type().Field(i).IsExported() {
+				dst.Field(i).Set(deepCopy(src.Field(i), visited))
+			}
+		}
+		return dst
+	}
+
+	return src
+}
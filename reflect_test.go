@@ -0,0 +1,219 @@
+package box
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestPresenceStats(t *testing.T) {
+	type Address struct {
+		City   Optional[string]
+		Street Optional[string]
+	}
+
+	type Request struct {
+		Name     Optional[string]
+		Age      Optional[int]
+		Email    Optional[string]
+		Home     Address
+		Work     *Address
+		Billing  Optional[Address]
+		Plain    string
+		internal Optional[int]
+	}
+
+	r := Request{
+		Name:    Some("John"),
+		Home:    Address{City: Some("Berlin")},
+		Work:    &Address{},
+		Billing: Some(Address{Street: Some("Main")}),
+	}
+
+	present, absent := PresenceStats(&r)
+	if present != 4 || absent != 6 {
+		t.Errorf("PresenceStats() = %d, %d; want 4, 6", present, absent)
+	}
+
+	present, absent = PresenceStats(Address{})
+	if present != 0 || absent != 2 {
+		t.Errorf("PresenceStats() = %d, %d; want 0, 2", present, absent)
+	}
+}
+
+func TestApplyDefaults(t *testing.T) {
+	type Limits struct {
+		Burst Optional[int] `box:"default=10"`
+	}
+
+	type Config struct {
+		Host    Optional[string] `box:"default=localhost"`
+		Port    Optional[int]    `box:"default=8080"`
+		Verbose Optional[bool]   `box:"default=true"`
+		Name    Optional[string]
+		Limits  Limits
+	}
+
+	var c Config
+	if err := json.Unmarshal([]byte(`{"Port": 9090}`), &c); err != nil {
+		t.Fatal(err)
+	}
+	if err := ApplyDefaults(&c); err != nil {
+		t.Fatal(err)
+	}
+
+	want := Config{
+		Host:    Some("localhost"),
+		Port:    Some(9090),
+		Verbose: Some(true),
+		Limits:  Limits{Burst: Some(10)},
+	}
+	if c != want {
+		t.Errorf("got %+v, want %+v", c, want)
+	}
+
+	var bad struct {
+		Port Optional[int] `box:"default=abc"`
+	}
+	if err := ApplyDefaults(&bad); err == nil {
+		t.Error("expected error on invalid default")
+	}
+
+	var unsupported struct {
+		Port int `box:"default=1"`
+	}
+	if err := ApplyDefaults(&unsupported); err == nil {
+		t.Error("expected error on non-optional field")
+	}
+
+	if err := ApplyDefaults(c); err == nil {
+		t.Error("expected error on non-pointer argument")
+	}
+}
+
+func TestDump(t *testing.T) {
+	type Address struct {
+		City Optional[string]
+	}
+
+	type User struct {
+		Name     Optional[string]
+		Age      Optional[int]
+		Nickname Optional2[string]
+		Home     Address
+		Plain    string
+		Email    *Optional[string]
+		Phone    *Optional[string]
+		Work     *Address
+	}
+
+	phone := Some("123")
+	var buf strings.Builder
+	Dump(&buf, &User{
+		Name:     Some("John"),
+		Nickname: Some2(None[string]()),
+		Home:     Address{City: Some("Berlin")},
+		Phone:    &phone,
+	})
+
+	want := "" +
+		"FIELD      STATE  VALUE\n" +
+		"Name       Some   John\n" +
+		"Age        None   \n" +
+		"Nickname   Some   None\n" +
+		"Home.City  Some   Berlin\n" +
+		"Email      nil    \n" +
+		"Phone      Some   123\n"
+	if buf.String() != want {
+		t.Errorf("got\n%s\nwant\n%s", buf.String(), want)
+	}
+}
+
+func TestDeepClone(t *testing.T) {
+	type node struct {
+		Name string
+		Next *node
+	}
+	type profile struct {
+		Tags    []string
+		Attrs   map[string][]int
+		Owner   *node
+		Nick    Optional[[]byte]
+		Extra   any
+		Parent  Optional2[[]string]
+		History [2][]int
+	}
+
+	loop := &node{Name: "a"}
+	loop.Next = loop
+
+	orig := Some(profile{
+		Tags:    []string{"x", "y"},
+		Attrs:   map[string][]int{"k": {1, 2}},
+		Owner:   loop,
+		Nick:    Some([]byte("nick")),
+		Extra:   []int{7},
+		Parent:  Some2(Some([]string{"p"})),
+		History: [2][]int{{1}, {2}},
+	})
+	want := fmt.Sprintf("%v", orig.Get())
+
+	clone := DeepClone(orig)
+	c := clone.Get()
+	c.Tags[0] = "changed"
+	c.Attrs["k"][0] = 100
+	c.Owner.Name = "changed"
+	c.Nick.Get()[0] = 'N'
+	c.Extra.([]int)[0] = 100
+	c.Parent.Get().Get()[0] = "changed"
+	c.History[0][0] = 100
+
+	if got := fmt.Sprintf("%v", orig.Get()); got != want {
+		t.Errorf("original changed:\ngot  %s\nwant %s", got, want)
+	}
+	if orig.Get().Owner.Name != "a" {
+		t.Error("original pointed value changed")
+	}
+	if c.Owner.Next != c.Owner {
+		t.Error("cycle is not preserved")
+	}
+
+	if got := DeepClone(None[[]int]()); got.IsSome() {
+		t.Errorf("None: got %v", got)
+	}
+	if got := DeepClone(Some[any](nil)); got != Some[any](nil) {
+		t.Errorf("nil interface: got %v", got)
+	}
+}
+
+func TestDeepClone_pointersThroughOptional(t *testing.T) {
+	type cyc struct {
+		Name string
+		Next Optional[*cyc]
+	}
+
+	n := &cyc{Name: "a"}
+	n.Next = Some(n)
+
+	c := DeepClone(Some(n)).Get()
+	if c == n {
+		t.Fatal("pointer is not copied")
+	}
+	if c.Next.Get() != c {
+		t.Error("cycle through Optional is not preserved")
+	}
+
+	type pair struct {
+		First, Second Optional[*cyc]
+	}
+
+	shared := &cyc{Name: "s"}
+	p := DeepClone(Some(pair{Some(shared), Some(shared)})).Get()
+	if p.First.Get() == shared {
+		t.Fatal("shared pointer is not copied")
+	}
+	if p.First.Get() != p.Second.Get() {
+		t.Error("pointer shared by two Optional fields is copied twice")
+	}
+}
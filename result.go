@@ -0,0 +1,78 @@
+package box
+
+import "encoding/json"
+
+// Result represents the result of a fallible computation: a value of type T ([Ok])
+// or an error ([Err]). Zero value of Result is Ok with the zero value of T.
+//
+// Result implements marshalling to JSON: Ok value is presented as the underlying value,
+// marshalling of Err value returns its error.
+type Result[T any] struct {
+	v   T
+	err error
+}
+
+// Ok returns successful [Result] with the given value.
+func Ok[T any](val T) Result[T] {
+	return Result[T]{v: val}
+}
+
+// Err returns failed [Result] with the given error. Panics if err is nil.
+func Err[T any](err error) Result[T] {
+	if err == nil {
+		panic("box: Err called with nil error")
+	}
+
+	return Result[T]{err: err}
+}
+
+// IsOk returns true if the [Result] holds a value.
+func (r Result[T]) IsOk() bool {
+	return r.err == nil
+}
+
+// IsErr returns true if the [Result] holds an error.
+func (r Result[T]) IsErr() bool {
+	return r.err != nil
+}
+
+// Get returns underlying value if [Result] is [Ok].
+// Panics in case [Result] is [Err].
+func (r Result[T]) Get() T {
+	if r.err != nil {
+		panic("result is error: " + r.err.Error())
+	}
+
+	return r.v
+}
+
+// Err returns the error if [Result] is [Err], otherwise nil.
+func (r Result[T]) Err() error {
+	return r.err
+}
+
+// Take returns underlying value and nil if [Result] is [Ok],
+// otherwise the zero value of T and the error.
+func (r Result[T]) Take() (T, error) {
+	return r.v, r.err
+}
+
+// ToOptional returns [Some] with the underlying value if [Result] is [Ok], otherwise [None].
+func (r Result[T]) ToOptional() Optional[T] {
+	if r.err != nil {
+		return None[T]()
+	}
+
+	return Some(r.v)
+}
+
+var _ json.Marshaler = Result[any]{}
+
+// MarshalJSON implements [json.Marshaler]. For [Err] value it returns the error of the [Result].
+func (r Result[T]) MarshalJSON() ([]byte, error) {
+	if r.err != nil {
+		return nil, r.err
+	}
+
+	return Some(r.v).MarshalJSON()
+}
@@ -0,0 +1,74 @@
+package box
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func TestResult(t *testing.T) {
+	errFail := errors.New("fail")
+
+	ok := Ok(5)
+	if !ok.IsOk() || ok.IsErr() {
+		t.Error("Ok: IsOk/IsErr mismatch")
+	}
+	if ok.Get() != 5 || ok.Err() != nil {
+		t.Errorf("Ok: Get=%v Err=%v, want 5 and nil", ok.Get(), ok.Err())
+	}
+	if v, err := ok.Take(); v != 5 || err != nil {
+		t.Errorf("Ok: Take=%v, %v, want 5, nil", v, err)
+	}
+	if ok.ToOptional() != Some(5) {
+		t.Errorf("Ok: ToOptional=%v, want Some(5)", ok.ToOptional())
+	}
+
+	e := Err[int](errFail)
+	if e.IsOk() || !e.IsErr() {
+		t.Error("Err: IsOk/IsErr mismatch")
+	}
+	if e.Err() != errFail {
+		t.Errorf("Err: Err=%v, want %v", e.Err(), errFail)
+	}
+	if v, err := e.Take(); v != 0 || err != errFail {
+		t.Errorf("Err: Take=%v, %v, want 0, %v", v, err, errFail)
+	}
+	if e.ToOptional() != None[int]() {
+		t.Errorf("Err: ToOptional=%v, want None", e.ToOptional())
+	}
+
+	var zero Result[int]
+	if !zero.IsOk() || zero.Get() != 0 {
+		t.Error("zero value is not Ok(0)")
+	}
+}
+
+func TestResult_panics(t *testing.T) {
+	assertPanic := func(name string, f func()) {
+		t.Helper()
+		defer func() {
+			if recover() == nil {
+				t.Errorf("%s: expected panic", name)
+			}
+		}()
+		f()
+	}
+
+	assertPanic("Get of Err", func() { Err[int](errors.New("fail")).Get() })
+	assertPanic("Err with nil", func() { Err[int](nil) })
+}
+
+func TestResult_MarshalJSON(t *testing.T) {
+	b, err := json.Marshal(struct{ R Result[[]int] }{Ok([]int{1, 2})})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"R":[1,2]}`; string(b) != want {
+		t.Errorf("Ok: got %s, want %s", b, want)
+	}
+
+	errFail := errors.New("fail")
+	if _, err := json.Marshal(struct{ R Result[int] }{Err[int](errFail)}); !errors.Is(err, errFail) {
+		t.Errorf("Err: got %v, want %v", err, errFail)
+	}
+}
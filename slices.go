@@ -0,0 +1,234 @@
+package box
+
+import (
+	"errors"
+	"fmt"
+	"iter"
+)
+
+// GroupBy groups the items by the key returned by the key function.
+// Items with a [Some] key are grouped into the map, items with a [None] key are returned
+// in the separate slice. Order of the items within each group is preserved.
+func GroupBy[T comparable, V any](items []V, key func(V) Optional[T]) (map[T][]V, []V) {
+	groups := make(map[T][]V)
+	var none []V
+
+	for _, item := range items {
+		k := key(item)
+		if !k.some {
+			none = append(none, item)
+			continue
+		}
+
+		groups[k.v] = append(groups[k.v], item)
+	}
+
+	return groups, none
+}
+
+// StripNone returns a new map containing the unwrapped [Some] values of the given map.
+// Entries with [None] value are omitted.
+func StripNone[K comparable, V any](m map[K]Optional[V]) map[K]V {
+	res := make(map[K]V, len(m))
+	StripNoneInto(res, m)
+
+	return res
+}
+
+// StripNoneInto writes the unwrapped [Some] values of src into dst, overwriting existing entries.
+// Entries with [None] value are skipped and don't affect dst.
+func StripNoneInto[K comparable, V any](dst map[K]V, src map[K]Optional[V]) {
+	for k, opt := range src {
+		if opt.some {
+			dst[k] = opt.v
+		}
+	}
+}
+
+// ParseAll applies f to each element of the slice. The result of a successful call is stored as [Some],
+// the result of a failed one as [None]. Errors are returned positionally: errs[i] is the error of in[i]
+// or nil. Both returned slices have the same length as the input.
+func ParseAll[T, U any](in []T, f func(T) (U, error)) ([]Optional[U], []error) {
+	res := make([]Optional[U], len(in))
+	errs := make([]error, len(in))
+
+	for i, v := range in {
+		u, err := f(v)
+		if err != nil {
+			errs[i] = err
+			continue
+		}
+
+		res[i] = Some(u)
+	}
+
+	return res, errs
+}
+
+// ToArrowColumn converts the slice to parallel value and validity slices as columnar formats
+// like Apache Arrow expect. [None] value is presented by the zero value slot and false validity.
+func ToArrowColumn[T Numeric](opts []Optional[T]) (values []T, validity []bool) {
+	values = make([]T, len(opts))
+	validity = make([]bool, len(opts))
+
+	for i, opt := range opts {
+		values[i] = opt.v
+		validity[i] = opt.some
+	}
+
+	return values, validity
+}
+
+// FromArrowColumn builds a slice of [Optional] from parallel value and validity slices,
+// the inverse of [ToArrowColumn]. Values with false validity become [None].
+// Returns an error if the slices have different lengths.
+func FromArrowColumn[T any](values []T, validity []bool) ([]Optional[T], error) {
+	if len(values) != len(validity) {
+		return nil, fmt.Errorf("values length %d doesn't match validity length %d", len(values), len(validity))
+	}
+
+	res := make([]Optional[T], len(values))
+	for i, v := range values {
+		if validity[i] {
+			res[i] = Some(v)
+		}
+	}
+
+	return res, nil
+}
+
+// Sequence returns [Some] with all the values if every element of the slice is [Some],
+// otherwise [None]. It stops on the first [None] element.
+// An empty or nil slice gives Some([]T{}).
+func Sequence[T any](opts []Optional[T]) Optional[[]T] {
+	values := make([]T, 0, len(opts))
+	for _, opt := range opts {
+		if !opt.some {
+			return None[[]T]()
+		}
+
+		values = append(values, opt.v)
+	}
+
+	return Some(values)
+}
+
+// Traverse applies f to each element of the slice and returns [Some] with all the results
+// if every result is [Some], otherwise [None]. It stops on the first [None] result.
+// An empty slice gives Some([]U{}).
+func Traverse[T, U any](in []T, f func(T) Optional[U]) Optional[[]U] {
+	values := make([]U, 0, len(in))
+	for _, v := range in {
+		opt := f(v)
+		if !opt.some {
+			return None[[]U]()
+		}
+
+		values = append(values, opt.v)
+	}
+
+	return Some(values)
+}
+
+// FoldSeq folds the [Some] values of the sequence with f, starting from init. [None] values are skipped.
+// The folding stops early when f returns false; the accumulator returned by that call is the result.
+func FoldSeq[T, U any](seq iter.Seq[Optional[T]], init U, f func(U, T) (U, bool)) U {
+	acc := init
+	for opt := range seq {
+		if !opt.some {
+			continue
+		}
+
+		var more bool
+		if acc, more = f(acc, opt.v); !more {
+			break
+		}
+	}
+
+	return acc
+}
+
+// ToMap returns a map with the single entry if [Optional] is [Some], otherwise an empty non-nil map.
+// It is useful to add the entry to another map conditionally with [maps.Copy].
+func ToMap[K comparable, V any](key K, opt Optional[V]) map[K]V {
+	if !opt.some {
+		return map[K]V{}
+	}
+
+	return map[K]V{key: opt.v}
+}
+
+// Partition3 splits the [Optional2] values by their state: indices of unset ([None2]) entries,
+// indices of cleared (Some2(None)) entries, and the values of set entries by index.
+func Partition3[T any](os []Optional2[T]) (unset, cleared []int, set map[int]T) {
+	set = make(map[int]T)
+	for i, opt := range os {
+		switch {
+		case opt.IsNone():
+			unset = append(unset, i)
+		case opt.v.IsNone():
+			cleared = append(cleared, i)
+		default:
+			set[i] = opt.v.v
+		}
+	}
+
+	return unset, cleared, set
+}
+
+// IntersectSome returns the [Some] values present in both slices. [None] values are ignored.
+// The result has no duplicates and follows the order of the first occurrence in a.
+func IntersectSome[T comparable](a, b []Optional[T]) []T {
+	inB := make(map[T]struct{}, len(b))
+	for _, opt := range b {
+		if opt.some {
+			inB[opt.v] = struct{}{}
+		}
+	}
+
+	var res []T
+	for _, opt := range a {
+		if !opt.some {
+			continue
+		}
+		if _, ok := inB[opt.v]; ok {
+			res = append(res, opt.v)
+			delete(inB, opt.v)
+		}
+	}
+
+	return res
+}
+
+// CollectSome returns the values of the [Some] elements of the slice preserving their order.
+// Returns nil for a nil slice and a non-nil empty slice for an empty or all-[None] one.
+func CollectSome[T any](opts []Optional[T]) []T {
+	if opts == nil {
+		return nil
+	}
+
+	res := make([]T, 0, len(opts))
+	for _, opt := range opts {
+		if opt.some {
+			res = append(res, opt.v)
+		}
+	}
+
+	return res
+}
+
+// CollectErrors calls validate for each [Some] value and returns the errors joined by [errors.Join].
+// [None] values are skipped. Returns nil if all values are valid.
+func CollectErrors[T any](opts []Optional[T], validate func(T) error) error {
+	var errs []error
+	for _, opt := range opts {
+		if !opt.some {
+			continue
+		}
+		if err := validate(opt.v); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
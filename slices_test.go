@@ -0,0 +1,281 @@
+package box
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"strconv"
+	"testing"
+)
+
+func TestGroupBy(t *testing.T) {
+	type user struct {
+		name string
+		team Optional[string]
+	}
+
+	users := []user{
+		{"alice", Some("red")},
+		{"bob", None[string]()},
+		{"carol", Some("blue")},
+		{"dave", Some("red")},
+		{"eve", None[string]()},
+	}
+
+	groups, none := GroupBy(users, func(u user) Optional[string] { return u.team })
+
+	wantGroups := map[string][]user{
+		"red":  {users[0], users[3]},
+		"blue": {users[2]},
+	}
+	if !reflect.DeepEqual(groups, wantGroups) {
+		t.Errorf("groups = %v, want %v", groups, wantGroups)
+	}
+
+	wantNone := []user{users[1], users[4]}
+	if !reflect.DeepEqual(none, wantNone) {
+		t.Errorf("none = %v, want %v", none, wantNone)
+	}
+}
+
+func TestStripNone(t *testing.T) {
+	overrides := map[string]Optional[int]{
+		"timeout": Some(30),
+		"retries": None[int](),
+		"workers": Some(0),
+	}
+
+	got := StripNone(overrides)
+	want := map[string]int{"timeout": 30, "workers": 0}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("StripNone() = %v, want %v", got, want)
+	}
+
+	dst := map[string]int{"timeout": 10, "retries": 3}
+	StripNoneInto(dst, overrides)
+	want = map[string]int{"timeout": 30, "retries": 3, "workers": 0}
+	if !reflect.DeepEqual(dst, want) {
+		t.Errorf("StripNoneInto() = %v, want %v", dst, want)
+	}
+}
+
+func TestParseAll(t *testing.T) {
+	res, errs := ParseAll([]string{"1", "x", "3", ""}, strconv.Atoi)
+
+	want := []Optional[int]{Some(1), None[int](), Some(3), None[int]()}
+	if !reflect.DeepEqual(res, want) {
+		t.Errorf("res = %v, want %v", res, want)
+	}
+
+	if len(errs) != 4 {
+		t.Fatalf("len(errs) = %d, want 4", len(errs))
+	}
+	for i, failed := range []bool{false, true, false, true} {
+		if (errs[i] != nil) != failed {
+			t.Errorf("errs[%d] = %v, want failed=%v", i, errs[i], failed)
+		}
+	}
+}
+
+func TestToArrowColumn(t *testing.T) {
+	values, validity := ToArrowColumn([]Optional[float64]{Some(1.5), None[float64](), Some(-2.0)})
+
+	if want := []float64{1.5, 0, -2}; !reflect.DeepEqual(values, want) {
+		t.Errorf("values = %v, want %v", values, want)
+	}
+	if want := []bool{true, false, true}; !reflect.DeepEqual(validity, want) {
+		t.Errorf("validity = %v, want %v", validity, want)
+	}
+}
+
+func TestFromArrowColumn(t *testing.T) {
+	tests := []struct {
+		name     string
+		values   []int
+		validity []bool
+		want     []Optional[int]
+	}{
+		{"mixed", []int{1, 0, 3}, []bool{true, false, true}, []Optional[int]{Some(1), None[int](), Some(3)}},
+		{"all null", []int{7, 8}, []bool{false, false}, []Optional[int]{None[int](), None[int]()}},
+		{"all valid", []int{0, 8}, []bool{true, true}, []Optional[int]{Some(0), Some(8)}},
+		{"empty", nil, nil, []Optional[int]{}},
+	}
+
+	for _, tt := range tests {
+		got, err := FromArrowColumn(tt.values, tt.validity)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", tt.name, err)
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: got %v, want %v", tt.name, got, tt.want)
+		}
+	}
+
+	if _, err := FromArrowColumn([]int{1, 2}, []bool{true}); err == nil {
+		t.Error("expected error on length mismatch")
+	}
+}
+
+func TestSequence(t *testing.T) {
+	got := Sequence([]Optional[int]{Some(1), Some(2)})
+	if !got.IsSome() || !reflect.DeepEqual(got.Get(), []int{1, 2}) {
+		t.Errorf("all present: got %v, want Some([1 2])", got)
+	}
+
+	if got := Sequence([]Optional[int]{Some(1), None[int](), Some(3)}); !got.IsNone() {
+		t.Errorf("one missing: got %v, want None", got)
+	}
+
+	for _, in := range [][]Optional[int]{{}, nil} {
+		got = Sequence(in)
+		if !got.IsSome() || got.Get() == nil || len(got.Get()) != 0 {
+			t.Errorf("%#v: got %v, want Some([])", in, got)
+		}
+	}
+}
+
+func TestTraverse(t *testing.T) {
+	calls := 0
+	parse := func(s string) Optional[int] {
+		calls++
+		v, err := strconv.Atoi(s)
+		if err != nil {
+			return None[int]()
+		}
+		return Some(v)
+	}
+
+	got := Traverse([]string{"1", "2", "3"}, parse)
+	if !got.IsSome() || !reflect.DeepEqual(got.Get(), []int{1, 2, 3}) {
+		t.Errorf("success: got %v, want Some([1 2 3])", got)
+	}
+
+	calls = 0
+	if got := Traverse([]string{"1", "x", "3"}, parse); !got.IsNone() {
+		t.Errorf("abort: got %v, want None", got)
+	}
+	if calls != 2 {
+		t.Errorf("abort: f called %d times, want 2", calls)
+	}
+}
+
+func TestFoldSeq(t *testing.T) {
+	opts := []Optional[int]{Some(5), None[int](), Some(10), Some(20), Some(40)}
+
+	visited := 0
+	sumUntil := func(acc, v int) (int, bool) {
+		visited++
+		acc += v
+		return acc, acc < 15
+	}
+
+	if got := FoldSeq(slices.Values(opts), 0, sumUntil); got != 15 {
+		t.Errorf("FoldSeq() = %d, want 15", got)
+	}
+	if visited != 2 {
+		t.Errorf("f called %d times, want 2", visited)
+	}
+
+	sum := func(acc, v int) (int, bool) { return acc + v, true }
+	if got := FoldSeq(slices.Values(opts), 1, sum); got != 76 {
+		t.Errorf("FoldSeq() = %d, want 76", got)
+	}
+}
+
+func TestToMap(t *testing.T) {
+	if got := ToMap("a", Some(1)); !reflect.DeepEqual(got, map[string]int{"a": 1}) {
+		t.Errorf("Some: got %v, want map[a:1]", got)
+	}
+
+	got := ToMap("a", None[int]())
+	if got == nil || len(got) != 0 {
+		t.Errorf("None: got %#v, want empty non-nil map", got)
+	}
+	got["b"] = 2 // must not panic
+}
+
+func TestPartition3(t *testing.T) {
+	in := []Optional2[string]{
+		Some2(Some("a")),
+		None2[string](),
+		Some2(None[string]()),
+		Some2(Some("b")),
+		None2[string](),
+	}
+
+	unset, cleared, set := Partition3(in)
+
+	if want := []int{1, 4}; !reflect.DeepEqual(unset, want) {
+		t.Errorf("unset = %v, want %v", unset, want)
+	}
+	if want := []int{2}; !reflect.DeepEqual(cleared, want) {
+		t.Errorf("cleared = %v, want %v", cleared, want)
+	}
+	if want := map[int]string{0: "a", 3: "b"}; !reflect.DeepEqual(set, want) {
+		t.Errorf("set = %v, want %v", set, want)
+	}
+}
+
+func TestIntersectSome(t *testing.T) {
+	a := []Optional[int]{Some(3), None[int](), Some(1), Some(2), Some(3)}
+	b := []Optional[int]{Some(2), Some(3), None[int](), Some(3), Some(4)}
+
+	if got, want := IntersectSome(a, b), []int{3, 2}; !reflect.DeepEqual(got, want) {
+		t.Errorf("overlapping: got %v, want %v", got, want)
+	}
+
+	disjoint := []Optional[int]{Some(7), None[int]()}
+	if got := IntersectSome(a, disjoint); len(got) != 0 {
+		t.Errorf("disjoint: got %v, want empty", got)
+	}
+}
+
+func TestCollectErrors(t *testing.T) {
+	calls := 0
+	positive := func(v int) error {
+		calls++
+		if v <= 0 {
+			return fmt.Errorf("%d is not positive", v)
+		}
+		return nil
+	}
+
+	err := CollectErrors([]Optional[int]{Some(1), None[int](), Some(-1), Some(0), None[int]()}, positive)
+	if want := "-1 is not positive\n0 is not positive"; err == nil || err.Error() != want {
+		t.Errorf("got %v, want %q", err, want)
+	}
+	if calls != 3 {
+		t.Errorf("validate called %d times, want 3", calls)
+	}
+	if u, ok := err.(interface{ Unwrap() []error }); !ok || len(u.Unwrap()) != 2 {
+		t.Errorf("got %T, want joined error of 2 errors", err)
+	}
+
+	if err := CollectErrors([]Optional[int]{Some(1), None[int]()}, positive); err != nil {
+		t.Errorf("valid: got %v, want nil", err)
+	}
+	if err := CollectErrors(nil, positive); err != nil {
+		t.Errorf("nil: got %v, want nil", err)
+	}
+}
+
+func TestCollectSome(t *testing.T) {
+	tests := []struct {
+		name string
+		in   []Optional[int]
+		want []int
+	}{
+		{"mixed", []Optional[int]{Some(1), None[int](), Some(3)}, []int{1, 3}},
+		{"all some", []Optional[int]{Some(1), Some(2)}, []int{1, 2}},
+		{"all none", []Optional[int]{None[int](), None[int]()}, []int{}},
+		{"empty", []Optional[int]{}, []int{}},
+		{"nil", nil, nil},
+	}
+
+	for _, tt := range tests {
+		got := CollectSome(tt.in)
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: got %#v, want %#v", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,210 @@
+package box
+
+import (
+	"database/sql"
+	"encoding"
+	"fmt"
+	"iter"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// ScanError is returned by [Optional.Scan] when the source value can't be converted to the [Optional].
+type ScanError struct {
+	Src  reflect.Type // type of the source value
+	Dest reflect.Type // type of the Optional
+	Err  error        // underlying conversion error
+}
+
+func (e *ScanError) Error() string {
+	return fmt.Sprintf("unable to scan %v into %v: %v", e.Src, e.Dest, e.Err)
+}
+
+func (e *ScanError) Unwrap() error {
+	return e.Err
+}
+
+// ScanText parses the text column value into the [Optional]. NULL is presented as [None].
+// It is useful for drivers returning all the columns as text.
+// The text is parsed with UnmarshalText method if T implements [encoding.TextUnmarshaler],
+// otherwise the same conversion rules as for [sql.Rows.Scan] are applied.
+func (opt *Optional[T]) ScanText(s sql.NullString) error {
+	if !s.Valid {
+		*opt = None[T]()
+		return nil
+	}
+
+	return opt.parseText(s.String)
+}
+
+// parseText parses the text into [Some] value, see [Optional.ScanText] for the parsing rules.
+func (opt *Optional[T]) parseText(s string) error {
+	var v T
+	if u, ok := any(&v).(encoding.TextUnmarshaler); ok {
+		if err := u.UnmarshalText([]byte(s)); err != nil {
+			*opt = None[T]()
+			return err
+		}
+
+		*opt = Some(v)
+		return nil
+	}
+
+	var n sql.Null[T]
+	if err := n.Scan(s); err != nil {
+		*opt = None[T]()
+		return err
+	}
+
+	*opt = Some(n.V)
+	return nil
+}
+
+// IterRows returns an iterator over the transformed [Some] values of the result set.
+// Each row is scanned by the scan function, [None] values are skipped, and [Some] values are
+// transformed by f. A scan error is yielded with the zero value of U and the iteration continues
+// unless the caller stops it. The error of the result set is yielded last.
+// The iterator closes the rows when done.
+func IterRows[T, U any](rows *sql.Rows, scan func(*sql.Rows) (Optional[T], error), f func(T) U) iter.Seq2[U, error] {
+	return func(yield func(U, error) bool) {
+		defer rows.Close()
+
+		var zero U
+		for rows.Next() {
+			opt, err := scan(rows)
+			if err != nil {
+				if !yield(zero, err) {
+					return
+				}
+				continue
+			}
+
+			if opt.some && !yield(f(opt.v), nil) {
+				return
+			}
+		}
+
+		if err := rows.Err(); err != nil {
+			yield(zero, err)
+		}
+	}
+}
+
+// FromSQLNull returns [Some] with the value of n if n is valid, otherwise [None].
+func FromSQLNull[T any](n sql.Null[T]) Optional[T] {
+	if !n.Valid {
+		return None[T]()
+	}
+
+	return Some(n.V)
+}
+
+// ToSQLNull returns valid [sql.Null] with the underlying value if [Optional] is [Some],
+// otherwise invalid one.
+func (opt Optional[T]) ToSQLNull() sql.Null[T] {
+	return sql.Null[T]{V: opt.v, Valid: opt.some}
+}
+
+// UpdateArg returns the assignment fragment and the argument for the column of an UPDATE statement.
+// [Some] yields ("col = ?", value, true). [None] yields ("", nil, false), so the column should be skipped.
+// It is useful to build dynamic UPDATE statements from PATCH forms.
+func (opt Optional[T]) UpdateArg(col string) (frag string, arg any, set bool) {
+	if !opt.some {
+		return "", nil, false
+	}
+
+	return col + " = ?", opt.v, true
+}
+
+// ScanStruct scans the current row into the struct pointed to by dest.
+// Columns are matched to the exported fields by `db:"name"` tag or, if there is no tag,
+// by the field name case-insensitively. Columns without matching field are discarded.
+// Fields of type [Optional] get [None] for NULL columns.
+// Nil pointers to embedded structs are allocated for the columns matching their fields.
+func ScanStruct(rows *sql.Rows, dest any) error {
+	rv := reflect.ValueOf(dest)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("dest must be a non-nil pointer to struct, got %T", dest)
+	}
+	rv = rv.Elem()
+
+	cols, err := rows.Columns()
+	if err != nil {
+		return err
+	}
+
+	fields := make(map[string][]int)
+	for _, f := range reflect.VisibleFields(rv.Type()) {
+		if !f.IsExported() || f.Anonymous {
+			continue
+		}
+
+		name := f.Tag.Get("db")
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = strings.ToLower(f.Name)
+		}
+		fields[name] = f.Index
+	}
+
+	targets := make([]any, len(cols))
+	for i, col := range cols {
+		index, ok := fields[col]
+		if !ok {
+			index, ok = fields[strings.ToLower(col)]
+		}
+		if !ok {
+			targets[i] = new(any)
+			continue
+		}
+
+		fv, err := fieldByIndexAlloc(rv, index)
+		if err != nil {
+			return fmt.Errorf("column %q: %w", col, err)
+		}
+		targets[i] = fv.Addr().Interface()
+	}
+
+	return rows.Scan(targets...)
+}
+
+// fieldByIndexAlloc returns the nested field like [reflect.Value.FieldByIndex] does,
+// but allocates nil embedded pointers on the path instead of panicking.
+func fieldByIndexAlloc(rv reflect.Value, index []int) (reflect.Value, error) {
+	for i, x := range index {
+		if i > 0 && rv.Kind() == reflect.Pointer {
+			if rv.IsNil() {
+				if !rv.CanSet() {
+					return reflect.Value{}, fmt.Errorf("can't allocate nil embedded pointer to unexported %v", rv.Type().Elem())
+				}
+				rv.Set(reflect.New(rv.Type().Elem()))
+			}
+			rv = rv.Elem()
+		}
+		rv = rv.Field(x)
+	}
+
+	return rv, nil
+}
+
+// ScanInLocation scans the value like Scan does and converts the scanned time to the given location.
+// It is only applicable to Optional[time.Time], for other types it returns an error.
+func (opt *Optional[T]) ScanInLocation(src any, loc *time.Location) error {
+	p, ok := any(&opt.v).(*time.Time)
+	if !ok {
+		return fmt.Errorf("ScanInLocation: Optional[time.Time] expected, got %T", *opt)
+	}
+
+	if err := opt.Scan(src); err != nil {
+		return err
+	}
+
+	if opt.some {
+		*p = p.In(loc)
+	}
+
+	return nil
+}
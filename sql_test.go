@@ -0,0 +1,300 @@
+package box
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestOptional_ScanText(t *testing.T) {
+	var num Optional[int64]
+	if err := num.ScanText(sql.NullString{String: "42", Valid: true}); err != nil {
+		t.Fatal(err)
+	}
+	if num != Some[int64](42) {
+		t.Errorf("got %v, want Some(42)", num)
+	}
+
+	if err := num.ScanText(sql.NullString{}); err != nil {
+		t.Fatal(err)
+	}
+	if num != None[int64]() {
+		t.Errorf("got %v, want None", num)
+	}
+
+	if err := num.ScanText(sql.NullString{String: "abc", Valid: true}); err == nil {
+		t.Error("expected error on invalid number")
+	}
+
+	var ts Optional[time.Time]
+	if err := ts.ScanText(sql.NullString{String: "2024-01-02T03:04:05Z", Valid: true}); err != nil {
+		t.Fatal(err)
+	}
+	if want := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC); !ts.IsSome() || !ts.Get().Equal(want) {
+		t.Errorf("got %v, want Some(%v)", ts, want)
+	}
+}
+
+func TestIterRows(t *testing.T) {
+	rows := testRowsDB(t, []string{"age"}, [][]driver.Value{
+		{int64(10)},
+		{nil},
+		{"abc"},
+		{int64(20)},
+	})
+
+	scan := func(rows *sql.Rows) (Optional[int], error) {
+		var opt Optional[int]
+		err := rows.Scan(&opt)
+		return opt, err
+	}
+	double := func(v int) int { return v * 2 }
+
+	var (
+		values []int
+		errs   int
+	)
+	for v, err := range IterRows(rows, scan, double) {
+		if err != nil {
+			errs++
+			continue
+		}
+		values = append(values, v)
+	}
+
+	if !reflect.DeepEqual(values, []int{20, 40}) {
+		t.Errorf("values = %v, want [20 40]", values)
+	}
+	if errs != 1 {
+		t.Errorf("got %d errors, want 1", errs)
+	}
+}
+
+func TestOptional_UpdateArg(t *testing.T) {
+	frag, arg, set := Some("John").UpdateArg("name")
+	if frag != "name = ?" || arg != "John" || !set {
+		t.Errorf("Some: got (%q, %v, %v)", frag, arg, set)
+	}
+
+	frag, arg, set = None[string]().UpdateArg("name")
+	if frag != "" || arg != nil || set {
+		t.Errorf("None: got (%q, %v, %v)", frag, arg, set)
+	}
+}
+
+func TestScanStruct(t *testing.T) {
+	type User struct {
+		ID         int64
+		Name       Optional[string]
+		MiddleName Optional[string] `db:"middle_name"`
+		Age        Optional[int]
+		Ignored    Optional[int] `db:"-"`
+	}
+
+	rows := testRowsDB(t, []string{"id", "NAME", "middle_name", "age", "extra"}, [][]driver.Value{
+		{int64(1), "John", nil, int64(42), "x"},
+		{int64(2), nil, "Q", nil, nil},
+	})
+
+	want := []User{
+		{ID: 1, Name: Some("John"), Age: Some(42)},
+		{ID: 2, MiddleName: Some("Q")},
+	}
+
+	var got []User
+	for rows.Next() {
+		var u User
+		if err := ScanStruct(rows, &u); err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, u)
+	}
+	if err := rows.Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+}
+
+func TestScanStruct_embeddedPointer(t *testing.T) {
+	type Base struct {
+		ID int64
+	}
+	type Audit struct {
+		Author Optional[string]
+	}
+	type User struct {
+		*Base
+		*Audit
+		Name Optional[string]
+	}
+
+	rows := testRowsDB(t, []string{"id", "name"}, [][]driver.Value{{int64(1), "John"}})
+	rows.Next()
+
+	var u User
+	if err := ScanStruct(rows, &u); err != nil {
+		t.Fatal(err)
+	}
+	if u.Base == nil || u.ID != 1 || u.Name != Some("John") {
+		t.Errorf("got %+v, want ID 1 and Name John", u)
+	}
+	if u.Audit != nil {
+		t.Error("pointer without matching columns is allocated")
+	}
+
+	type base struct {
+		ID int64
+	}
+	var hidden struct{ *base }
+	rows = testRowsDB(t, []string{"id"}, [][]driver.Value{{int64(1)}})
+	rows.Next()
+	if err := ScanStruct(rows, &hidden); err == nil {
+		t.Error("nil unexported embedded pointer: expected error")
+	}
+}
+
+func TestScanStruct_invalidDest(t *testing.T) {
+	rows := testRowsDB(t, []string{"id"}, [][]driver.Value{{int64(1)}})
+	rows.Next()
+
+	var id int64
+	if err := ScanStruct(rows, &id); err == nil {
+		t.Error("expected error for non-struct dest")
+	}
+}
+
+func TestOptional_ScanInLocation(t *testing.T) {
+	loc := time.FixedZone("UTC+3", 3*60*60)
+	utc := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	var opt Optional[time.Time]
+	if err := opt.ScanInLocation(utc, loc); err != nil {
+		t.Fatal(err)
+	}
+	if !opt.IsSome() || opt.Get().Location() != loc || !opt.Get().Equal(utc) {
+		t.Errorf("got %v, want %v in %v", opt, utc, loc)
+	}
+
+	if err := opt.ScanInLocation(nil, loc); err != nil {
+		t.Fatal(err)
+	}
+	if !opt.IsNone() {
+		t.Errorf("got %v, want None", opt)
+	}
+
+	var num Optional[int]
+	if err := num.ScanInLocation(int64(1), loc); err == nil {
+		t.Error("expected error for non-time Optional")
+	}
+}
+
+func TestOptional_Scan_error(t *testing.T) {
+	opt := Some(1)
+	err := opt.Scan("abc")
+	if opt != None[int]() {
+		t.Errorf("got %v, want None after error", opt)
+	}
+
+	var scanErr *ScanError
+	if !errors.As(err, &scanErr) {
+		t.Fatalf("got %v, want *ScanError", err)
+	}
+	if scanErr.Src != reflect.TypeFor[string]() {
+		t.Errorf("Src = %v, want string", scanErr.Src)
+	}
+	if scanErr.Dest != reflect.TypeFor[Optional[int]]() {
+		t.Errorf("Dest = %v, want Optional[int]", scanErr.Dest)
+	}
+	if scanErr.Err == nil {
+		t.Error("Err is nil")
+	}
+
+	rows := testRowsDB(t, []string{"age"}, [][]driver.Value{{"abc"}})
+	rows.Next()
+	if err := rows.Scan(&opt); !errors.As(err, &scanErr) {
+		t.Errorf("rows.Scan: got %v, want *ScanError", err)
+	}
+}
+
+func TestOptional_Scan_nullWrappers(t *testing.T) {
+	var s Optional[string]
+	if err := s.Scan(sql.NullString{String: "a", Valid: true}); err != nil {
+		t.Fatal(err)
+	}
+	if s != Some("a") {
+		t.Errorf("valid NullString: got %v, want Some(a)", s)
+	}
+
+	if err := s.Scan(sql.NullString{}); err != nil {
+		t.Fatal(err)
+	}
+	if s != None[string]() {
+		t.Errorf("invalid NullString: got %v, want None", s)
+	}
+
+	var n Optional[int64]
+	if err := n.Scan(sql.NullInt64{Int64: 5, Valid: true}); err != nil {
+		t.Fatal(err)
+	}
+	if n != Some[int64](5) {
+		t.Errorf("valid NullInt64: got %v, want Some(5)", n)
+	}
+
+}
+
+func TestOptional_Scan_unsigned(t *testing.T) {
+	var u32 Optional[uint32]
+	if err := u32.Scan(int64(5)); err != nil {
+		t.Fatal(err)
+	}
+	if u32 != Some[uint32](5) {
+		t.Errorf("positive: got %v, want Some(5)", u32)
+	}
+
+	if err := u32.Scan(nil); err != nil {
+		t.Fatal(err)
+	}
+	if u32 != None[uint32]() {
+		t.Errorf("NULL: got %v, want None", u32)
+	}
+
+	u64 := Some[uint64](1)
+	if err := u64.Scan(int64(-5)); err == nil {
+		t.Error("negative: expected error")
+	}
+	if u64 != None[uint64]() {
+		t.Errorf("negative: got %v, want None after error", u64)
+	}
+
+	var u8 Optional[uint8]
+	if err := u8.Scan(int64(300)); err == nil {
+		t.Error("overflow: expected error")
+	}
+}
+
+func TestSQLNull(t *testing.T) {
+	for _, opt := range []Optional[string]{Some("a"), Some(""), None[string]()} {
+		n := opt.ToSQLNull()
+		if n.Valid != opt.IsSome() || n.V != opt.GetOrZero() {
+			t.Errorf("%v: ToSQLNull = %+v", opt, n)
+		}
+		if got := FromSQLNull(n); got != opt {
+			t.Errorf("%v: round-trip got %v", opt, got)
+		}
+
+		var valuer driver.Valuer = n
+		v, err := valuer.Value()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want, _ := opt.Value(); v != want {
+			t.Errorf("%v: Value = %v, want %v", opt, v, want)
+		}
+	}
+}
@@ -0,0 +1,39 @@
+package box
+
+import "encoding"
+
+var (
+	_ encoding.TextMarshaler   = Optional[any]{}
+	_ encoding.TextUnmarshaler = (*Optional[any])(nil)
+)
+
+// MarshalText implements [encoding.TextMarshaler], so [Optional] can be used e.g. as a JSON map key.
+// [None] value is presented as empty text. [Some] value is formatted by the same rules as
+// [Optional.MarshalXMLAttr].
+//
+// Note that Some of a value presented as empty text (e.g. Some("")) can't be distinguished from [None]
+// and is decoded by [Optional.UnmarshalText] as [None].
+func (opt Optional[T]) MarshalText() ([]byte, error) {
+	if !opt.some {
+		return []byte{}, nil
+	}
+
+	s, err := marshalText(opt.v)
+	if err != nil {
+		return nil, err
+	}
+
+	return []byte(s), nil
+}
+
+// UnmarshalText implements [encoding.TextUnmarshaler]. Empty text is decoded as [None],
+// otherwise the text is parsed by the same rules as [Optional.ScanText].
+// In case of error the [Optional] is reset to [None] and the error is returned.
+func (opt *Optional[T]) UnmarshalText(text []byte) error {
+	if len(text) == 0 {
+		*opt = None[T]()
+		return nil
+	}
+
+	return opt.parseText(string(text))
+}
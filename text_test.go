@@ -0,0 +1,105 @@
+package box
+
+import (
+	"encoding"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+type upperText string
+
+func (u upperText) MarshalText() ([]byte, error) {
+	return []byte(strings.ToUpper(string(u))), nil
+}
+
+func (u *upperText) UnmarshalText(text []byte) error {
+	if strings.ToUpper(string(text)) != string(text) {
+		return errors.New("not upper case")
+	}
+	*u = upperText(strings.ToLower(string(text)))
+	return nil
+}
+
+func TestOptional_MarshalText(t *testing.T) {
+	ts := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	testText(t, Some(ts), "2024-05-01T12:00:00Z")
+	testText(t, None[time.Time](), "")
+	testText(t, Some(upperText("abc")), "ABC")
+	testText(t, None[upperText](), "")
+	testText(t, Some(42), "42")
+}
+
+func testText[T comparable](t *testing.T, opt Optional[T], want string) {
+	t.Helper()
+
+	b, err := opt.MarshalText()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != want {
+		t.Errorf("%v: MarshalText = %q, want %q", opt, b, want)
+	}
+
+	got := Some(*new(T))
+	if err := got.UnmarshalText(b); err != nil {
+		t.Fatal(err)
+	}
+	if got != opt {
+		t.Errorf("%q: UnmarshalText = %v, want %v", b, got, opt)
+	}
+}
+
+func TestOptional_MarshalText_error(t *testing.T) {
+	type point struct{ X, Y int }
+
+	tests := []struct {
+		name string
+		opt  encoding.TextMarshaler
+	}{
+		{"struct", Some(point{1, 2})},
+		{"pointer to struct", Some(&point{1, 2})},
+		{"map", Some(map[string]int{"a": 1})},
+		{"slice", Some([]int{1, 2})},
+		{"array", Some([2]int{1, 2})},
+	}
+
+	for _, tt := range tests {
+		if _, err := tt.opt.MarshalText(); err == nil {
+			t.Errorf("%s: expected error", tt.name)
+		}
+	}
+}
+
+func TestOptional_UnmarshalText_error(t *testing.T) {
+	opt := Some(upperText("abc"))
+	if err := opt.UnmarshalText([]byte("abc")); err == nil {
+		t.Error("expected error")
+	}
+	if opt != None[upperText]() {
+		t.Errorf("got %v, want None after error", opt)
+	}
+}
+
+func TestOptional_MarshalText_mapKey(t *testing.T) {
+	in := map[Optional[upperText]]int{Some(upperText("abc")): 1, None[upperText](): 2}
+
+	b, err := json.Marshal(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"":2,"ABC":1}`; string(b) != want {
+		t.Errorf("got %s, want %s", b, want)
+	}
+
+	var out map[Optional[upperText]]int
+	if err := json.Unmarshal(b, &out); err != nil {
+		t.Fatal(err)
+	}
+	if len(out) != 2 || out[Some(upperText("abc"))] != 1 || out[None[upperText]()] != 2 {
+		t.Errorf("round-trip: got %v, want %v", out, in)
+	}
+}
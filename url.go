@@ -0,0 +1,24 @@
+package box
+
+import "net/url"
+
+// AppendEachToValues adds a key=value pair to v for each element of the slice if [Optional] is [Some],
+// e.g. Some([]string{"a", "b"}) for key "tag" encodes as tag=a&tag=b. [None] adds nothing.
+// The elements are formatted by the same rules as [Optional.MarshalXMLAttr].
+//
+// It is a function rather than a method because Go doesn't allow methods of Optional[[]T].
+func AppendEachToValues[T any](v url.Values, key string, opt Optional[[]T]) error {
+	if !opt.some {
+		return nil
+	}
+
+	for _, e := range opt.v {
+		s, err := marshalText(e)
+		if err != nil {
+			return err
+		}
+		v.Add(key, s)
+	}
+
+	return nil
+}
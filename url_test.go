@@ -0,0 +1,26 @@
+package box
+
+import (
+	"net/url"
+	"testing"
+)
+
+func TestAppendEachToValues(t *testing.T) {
+	v := url.Values{"q": {"x"}}
+	if err := AppendEachToValues(v, "tag", Some([]string{"a", "b"})); err != nil {
+		t.Fatal(err)
+	}
+	if err := AppendEachToValues(v, "id", Some([]int{1, 2})); err != nil {
+		t.Fatal(err)
+	}
+	if err := AppendEachToValues(v, "none", None[[]string]()); err != nil {
+		t.Fatal(err)
+	}
+
+	if got, want := v.Encode(), "id=1&id=2&q=x&tag=a&tag=b"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+	if v.Has("none") {
+		t.Error("None added the key")
+	}
+}
@@ -0,0 +1,331 @@
+package box
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// EmptyStringNull is a variant of [Optional] for string types conflating empty string and null.
+// [None] is marshalled to JSON as "", both null and "" are unmarshalled as [None].
+// Note that Some("") is marshalled as "" too and can't survive a round-trip.
+//
+//	var name EmptyStringNull[string]
+//	name.Optional = Some("John")
+type EmptyStringNull[T ~string] struct {
+	Optional[T]
+}
+
+func (EmptyStringNull[T]) customJSON()     {}
+func (EmptyStringNull[T]) customNoneJSON() {}
+
+func (opt EmptyStringNull[T]) MarshalJSON() ([]byte, error) {
+	if opt.IsNone() {
+		return emptyStringStrBytes, nil
+	}
+
+	return json.Marshal(opt.v)
+}
+
+func (opt *EmptyStringNull[T]) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(data, nullStrBytes) || bytes.Equal(data, emptyStringStrBytes) {
+		opt.Optional = None[T]()
+		return nil
+	}
+
+	var v T
+	if err := json.Unmarshal(data, &v); err != nil {
+		opt.Optional = None[T]()
+		return err
+	}
+
+	opt.Optional = Some(v)
+	return nil
+}
+
+// Tagged is a variant of [Optional] for tagged-union JSON payloads.
+// [Some] value is marshalled as the JSON object of the value with the "type" discriminator
+// field set to Tag, e.g. {"type":"circle","radius":1}. T must be marshalled as a JSON object
+// without the "type" field.
+// [None] value is marshalled as null without the discriminator, so fields of type Tagged
+// should be annotated by `json:",omitzero"` to omit from the encoding.
+type Tagged[T any] struct {
+	Optional[T]
+	Tag string
+}
+
+// SomeTagged returns [Tagged] with the given discriminator and value.
+func SomeTagged[T any](tag string, val T) Tagged[T] {
+	return Tagged[T]{Some(val), tag}
+}
+
+func (Tagged[T]) customJSON() {}
+
+func (t Tagged[T]) MarshalJSON() ([]byte, error) {
+	if t.IsNone() {
+		return nullStrBytes, nil
+	}
+
+	obj, err := json.Marshal(t.v)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(obj) < 2 || obj[0] != '{' {
+		return nil, fmt.Errorf("Tagged: value of type %T must be marshalled as JSON object", t.v)
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(obj, &fields); err != nil {
+		return nil, err
+	}
+	if _, ok := fields["type"]; ok {
+		return nil, fmt.Errorf("Tagged: value of type %T already has the \"type\" field", t.v)
+	}
+
+	tag, err := json.Marshal(t.Tag)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString(`{"type":`)
+	buf.Write(tag)
+	if obj[1] != '}' {
+		buf.WriteByte(',')
+	}
+	buf.Write(obj[1:])
+
+	return buf.Bytes(), nil
+}
+
+func (t *Tagged[T]) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(data, nullStrBytes) {
+		*t = Tagged[T]{}
+		return nil
+	}
+
+	var disc struct {
+		Type string `json:"type"`
+	}
+	if err := json.Unmarshal(data, &disc); err != nil {
+		*t = Tagged[T]{}
+		return err
+	}
+
+	var v T
+	if err := json.Unmarshal(data, &v); err != nil {
+		*t = Tagged[T]{}
+		return err
+	}
+
+	*t = SomeTagged(disc.Type, v)
+	return nil
+}
+
+// Fixed is a variant of [Optional] for floating-point numbers marshalled to JSON
+// with the fixed number of decimal places, e.g. 12.50 for Prec = 2.
+// [None] value is presented as null. Prec isn't changed by unmarshalling.
+type Fixed[T ~float32 | ~float64] struct {
+	Optional[T]
+	Prec int
+}
+
+// SomeFixed returns [Fixed] with the given value and number of decimal places.
+func SomeFixed[T ~float32 | ~float64](val T, prec int) Fixed[T] {
+	return Fixed[T]{Some(val), prec}
+}
+
+func (Fixed[T]) customJSON() {}
+
+func (f Fixed[T]) MarshalJSON() ([]byte, error) {
+	if f.IsNone() {
+		return nullStrBytes, nil
+	}
+
+	v := float64(f.v)
+	if math.IsNaN(v) || math.IsInf(v, 0) {
+		return nil, fmt.Errorf("Fixed: unsupported value %v", v)
+	}
+
+	return strconv.AppendFloat(nil, v, 'f', f.Prec, bitSize[T]()), nil
+}
+
+func (f *Fixed[T]) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(data, nullStrBytes) {
+		f.Optional = None[T]()
+		return nil
+	}
+
+	var v T
+	if err := json.Unmarshal(data, &v); err != nil {
+		f.Optional = None[T]()
+		return err
+	}
+
+	f.Optional = Some(v)
+	return nil
+}
+
+func bitSize[T ~float32 | ~float64]() int {
+	return reflect.TypeFor[T]().Bits()
+}
+
+// NoneRepr provides the JSON presentation of [None] for [WithNoneRepr].
+// Implementations are expected to be empty structs, the zero value of the type is used.
+type NoneRepr interface {
+	NoneJSON() []byte
+}
+
+// NullRepr presents [None] as null, the same as [Optional] does.
+type NullRepr struct{}
+
+func (NullRepr) NoneJSON() []byte {
+	return nullStrBytes
+}
+
+// WithNoneRepr is a variant of [Optional] presenting [None] in JSON with the value provided by R,
+// e.g. {"__null":true} for gateways not accepting bare null.
+// Both the value of R and null are unmarshalled as [None].
+//
+//	type SentinelNull struct{}
+//
+//	func (SentinelNull) NoneJSON() []byte { return []byte(`{"__null":true}`) }
+//
+//	var name WithNoneRepr[string, SentinelNull]
+type WithNoneRepr[T any, R NoneRepr] struct {
+	Optional[T]
+}
+
+func (WithNoneRepr[T, R]) customJSON()     {}
+func (WithNoneRepr[T, R]) customNoneJSON() {}
+
+func (opt WithNoneRepr[T, R]) MarshalJSON() ([]byte, error) {
+	if opt.IsNone() {
+		var r R
+		return r.NoneJSON(), nil
+	}
+
+	return opt.Optional.MarshalJSON()
+}
+
+func (opt *WithNoneRepr[T, R]) UnmarshalJSON(data []byte) error {
+	var r R
+	if jsonEqual(data, r.NoneJSON()) {
+		opt.Optional = None[T]()
+		return nil
+	}
+
+	return opt.Optional.UnmarshalJSON(data)
+}
+
+// jsonEqual reports whether a and b are the same JSON texts ignoring insignificant whitespace.
+func jsonEqual(a, b []byte) bool {
+	var ca, cb bytes.Buffer
+	if json.Compact(&ca, a) != nil || json.Compact(&cb, b) != nil {
+		return false
+	}
+
+	return bytes.Equal(ca.Bytes(), cb.Bytes())
+}
+
+// DefaultProvider provides the default value for [DefaultOnNull].
+// Implementations are expected to be empty structs, the zero value of the type is used.
+type DefaultProvider[T any] interface {
+	Default() T
+}
+
+// DefaultOnNull is a variant of [Optional] unmarshalling JSON null as [Some] with the default value
+// provided by D, i.e. an explicit null means "reset to default". An absent field stays [None].
+// Marshalling is the same as for [Optional].
+//
+//	type DefaultLimit struct{}
+//
+//	func (DefaultLimit) Default() int { return 100 }
+//
+//	var limit DefaultOnNull[int, DefaultLimit]
+type DefaultOnNull[T any, D DefaultProvider[T]] struct {
+	Optional[T]
+}
+
+func (opt *DefaultOnNull[T, D]) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(data, nullStrBytes) {
+		var d D
+		opt.Optional = Some(d.Default())
+		return nil
+	}
+
+	return opt.Optional.UnmarshalJSON(data)
+}
+
+// Strict is a variant of [Optional] guarding against silent data loss in JSON:
+// marshalling of [Some] value returns an error if T (or the type T points to) is a struct
+// having fields, but none of them visible to [json.Marshal] (unexported, tagged `json:"-"`
+// or promoted from embedded structs having no visible fields), so it is presented as {}.
+// Empty structs are allowed, see [Optional] about presence flags.
+// Unmarshalling is the same as for [Optional].
+type Strict[T any] struct {
+	Optional[T]
+}
+
+func (Strict[T]) customJSON() {}
+
+func (opt Strict[T]) MarshalJSON() ([]byte, error) {
+	b, err := opt.Optional.MarshalJSON()
+	if err != nil || opt.IsNone() || !bytes.Equal(b, emptyObjectBytes) {
+		return b, err
+	}
+
+	rt := reflect.TypeFor[T]()
+	for rt.Kind() == reflect.Pointer {
+		rt = rt.Elem()
+	}
+	if rt.Kind() == reflect.Struct && rt.NumField() > 0 && !hasVisibleField(rt, map[reflect.Type]bool{}) {
+		return nil, fmt.Errorf("Strict: %v has no fields visible to JSON and is marshalled as {}", rt)
+	}
+
+	return b, nil
+}
+
+var emptyObjectBytes = []byte("{}")
+
+// hasVisibleField reports whether the struct type has fields visible to [json.Marshal],
+// following its rules for json:"-" tags and embedded structs. visited guards against
+// recursion through embedded pointers.
+func hasVisibleField(rt reflect.Type, visited map[reflect.Type]bool) bool {
+	if visited[rt] {
+		return false
+	}
+	visited[rt] = true
+
+	for i := range rt.NumField() {
+		f := rt.Field(i)
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+
+		if name, _, _ := strings.Cut(tag, ","); f.Anonymous && name == "" {
+			ft := f.Type
+			if ft.Kind() == reflect.Pointer {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				if hasVisibleField(ft, visited) {
+					return true
+				}
+				continue
+			}
+		}
+
+		if f.IsExported() {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,287 @@
+package box
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestEmptyStringNull(t *testing.T) {
+	type User struct {
+		Name EmptyStringNull[string]
+	}
+
+	b, err := json.Marshal(User{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"Name":""}`; string(b) != want {
+		t.Errorf("None: got %s, want %s", b, want)
+	}
+
+	b, err = json.Marshal(User{EmptyStringNull[string]{Some("John")}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"Name":"John"}`; string(b) != want {
+		t.Errorf("Some: got %s, want %s", b, want)
+	}
+
+	for _, input := range []string{`{"Name":""}`, `{"Name":null}`} {
+		u := User{EmptyStringNull[string]{Some("old")}}
+		if err := json.Unmarshal([]byte(input), &u); err != nil {
+			t.Fatal(err)
+		}
+		if !u.Name.IsNone() {
+			t.Errorf("%s: got %v, want None", input, u.Name)
+		}
+	}
+
+	var u User
+	if err := json.Unmarshal([]byte(`{"Name":"John"}`), &u); err != nil {
+		t.Fatal(err)
+	}
+	if u.Name.Optional != Some("John") {
+		t.Errorf("got %v, want Some(John)", u.Name)
+	}
+
+	if err := json.Unmarshal([]byte(`{"Name":1}`), &u); err == nil {
+		t.Error("expected error on type mismatch")
+	}
+}
+
+func TestTagged(t *testing.T) {
+	type Circle struct {
+		Radius float64 `json:"radius"`
+	}
+	type Empty struct{}
+
+	type Shape struct {
+		Circle Tagged[Circle] `json:"circle,omitzero"`
+		Empty  Tagged[Empty]  `json:"empty,omitzero"`
+	}
+
+	b, err := json.Marshal(Shape{Circle: SomeTagged("circle", Circle{Radius: 1})})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"circle":{"type":"circle","radius":1}}`; string(b) != want {
+		t.Errorf("got %s, want %s", b, want)
+	}
+
+	b, err = json.Marshal(Shape{Empty: SomeTagged("empty", Empty{})})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"empty":{"type":"empty"}}`; string(b) != want {
+		t.Errorf("got %s, want %s", b, want)
+	}
+
+	var s Shape
+	if err := json.Unmarshal([]byte(`{"circle":{"type":"circle","radius":2}}`), &s); err != nil {
+		t.Fatal(err)
+	}
+	if want := (Shape{Circle: SomeTagged("circle", Circle{Radius: 2})}); s != want {
+		t.Errorf("got %+v, want %+v", s, want)
+	}
+
+	if _, err := json.Marshal(SomeTagged("num", 1)); err == nil {
+		t.Error("expected error for non-object value")
+	}
+
+	type typed struct {
+		Type string `json:"type"`
+	}
+	if _, err := json.Marshal(SomeTagged("typed", typed{"x"})); err == nil {
+		t.Error("expected error for value with the type field")
+	}
+}
+
+func TestFixed(t *testing.T) {
+	type Order struct {
+		Total    Fixed[float64]
+		Discount Fixed[float32]
+	}
+
+	o := Order{
+		Total:    SomeFixed(12.5, 2),
+		Discount: Fixed[float32]{Prec: 2},
+	}
+
+	b, err := json.Marshal(o)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"Total":12.50,"Discount":null}`; string(b) != want {
+		t.Errorf("got %s, want %s", b, want)
+	}
+
+	decoded := Order{
+		Total:    Fixed[float64]{Prec: 2},
+		Discount: SomeFixed[float32](1, 2),
+	}
+	if err := json.Unmarshal(b, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if decoded != o {
+		t.Errorf("got %+v, want %+v", decoded, o)
+	}
+
+	if b, _ := json.Marshal(SomeFixed[float32](0.1, 3)); string(b) != "0.100" {
+		t.Errorf("float32: got %s, want 0.100", b)
+	}
+}
+
+func TestVariants_UnmarshalJSON_errors(t *testing.T) {
+	name := EmptyStringNull[string]{Some("John")}
+	if err := name.UnmarshalJSON([]byte(`1`)); err == nil || !name.IsNone() {
+		t.Errorf("EmptyStringNull: got %v, %v; want None and error", name, err)
+	}
+
+	tagged := SomeTagged("point", struct{ X int }{1})
+	if err := tagged.UnmarshalJSON([]byte(`{"X":"a"}`)); err == nil || !tagged.IsNone() {
+		t.Errorf("Tagged: got %v, %v; want None and error", tagged, err)
+	}
+
+	fixed := SomeFixed(1.5, 2)
+	if err := fixed.UnmarshalJSON([]byte(`"1.5"`)); err == nil || !fixed.IsNone() {
+		t.Errorf("Fixed: got %v, %v; want None and error", fixed, err)
+	}
+}
+
+type testSentinelNull struct{}
+
+func (testSentinelNull) NoneJSON() []byte {
+	return []byte(`{"__null": true}`)
+}
+
+func TestWithNoneRepr(t *testing.T) {
+	type Payload struct {
+		Name    WithNoneRepr[string, testSentinelNull]
+		Comment WithNoneRepr[string, NullRepr]
+	}
+
+	b, err := json.Marshal(Payload{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"Name":{"__null":true},"Comment":null}`; string(b) != want {
+		t.Errorf("None: got %s, want %s", b, want)
+	}
+
+	p := Payload{
+		Name:    WithNoneRepr[string, testSentinelNull]{Some("John")},
+		Comment: WithNoneRepr[string, NullRepr]{Some("hi")},
+	}
+	b, err = json.Marshal(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"Name":"John","Comment":"hi"}`; string(b) != want {
+		t.Errorf("Some: got %s, want %s", b, want)
+	}
+
+	for _, input := range []string{`{"Name":{"__null":true}}`, `{"Name":null}`} {
+		decoded := p
+		if err := json.Unmarshal([]byte(input), &decoded); err != nil {
+			t.Fatal(err)
+		}
+		if !decoded.Name.IsNone() {
+			t.Errorf("%s: got %v, want None", input, decoded.Name)
+		}
+	}
+}
+
+type testDefaultLimit struct{}
+
+func (testDefaultLimit) Default() int {
+	return 100
+}
+
+func TestDefaultOnNull(t *testing.T) {
+	type Settings struct {
+		Limit DefaultOnNull[int, testDefaultLimit] `json:",omitzero"`
+	}
+
+	tests := []struct {
+		input string
+		want  Optional[int]
+	}{
+		{`{"Limit":null}`, Some(100)},
+		{`{"Limit":5}`, Some(5)},
+		{`{}`, None[int]()},
+	}
+
+	for _, tt := range tests {
+		var s Settings
+		if err := json.Unmarshal([]byte(tt.input), &s); err != nil {
+			t.Fatal(err)
+		}
+		if s.Limit.Optional != tt.want {
+			t.Errorf("%s: got %v, want %v", tt.input, s.Limit.Optional, tt.want)
+		}
+	}
+
+	b, err := json.Marshal(Settings{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `{}`; string(b) != want {
+		t.Errorf("None: got %s, want %s", b, want)
+	}
+}
+
+func TestStrict(t *testing.T) {
+	type hidden struct{ secret string }
+	type visible struct {
+		Name string `json:",omitempty"`
+	}
+
+	if _, err := json.Marshal(Strict[hidden]{Some(hidden{"x"})}); err == nil {
+		t.Error("unexported-only struct: expected error")
+	}
+	if _, err := json.Marshal(Strict[*hidden]{Some(&hidden{"x"})}); err == nil {
+		t.Error("pointer to unexported-only struct: expected error")
+	}
+
+	type embedsHidden struct{ hidden }
+	if _, err := json.Marshal(Strict[embedsHidden]{Some(embedsHidden{hidden{"x"}})}); err == nil {
+		t.Error("embedded unexported-only struct: expected error")
+	}
+
+	type ignored struct {
+		Secret string `json:"-"`
+	}
+	if _, err := json.Marshal(Strict[ignored]{Some(ignored{"x"})}); err == nil {
+		t.Error("struct with ignored fields only: expected error")
+	}
+
+	type embedsVisible struct{ visible }
+	if _, err := json.Marshal(Strict[embedsVisible]{Some(embedsVisible{})}); err != nil {
+		t.Errorf("embedded struct with visible fields: %v", err)
+	}
+
+	if _, err := EncodeStructOmitNone(struct{ H Strict[hidden] }{Strict[hidden]{Some(hidden{"x"})}}); err == nil {
+		t.Error("EncodeStructOmitNone: expected error")
+	}
+
+	tests := []struct {
+		name string
+		in   any
+		want string
+	}{
+		{"none", Strict[hidden]{}, `null`},
+		{"empty struct", Strict[struct{}]{Some(struct{}{})}, `{}`},
+		{"omitted fields", Strict[visible]{Some(visible{})}, `{}`},
+		{"value", Strict[visible]{Some(visible{"a"})}, `{"Name":"a"}`},
+	}
+
+	for _, tt := range tests {
+		b, err := json.Marshal(tt.in)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if string(b) != tt.want {
+			t.Errorf("%s: got %s, want %s", tt.name, b, tt.want)
+		}
+	}
+}
@@ -0,0 +1,103 @@
+package box
+
+import (
+	"encoding"
+	"encoding/xml"
+	"fmt"
+	"reflect"
+)
+
+var (
+	_ xml.Marshaler       = Optional[any]{}
+	_ xml.Unmarshaler     = (*Optional[any])(nil)
+	_ xml.MarshalerAttr   = Optional[any]{}
+	_ xml.UnmarshalerAttr = (*Optional[any])(nil)
+)
+
+// xsiNamespace is the XML Schema instance namespace of the xsi:nil attribute.
+const xsiNamespace = "http://www.w3.org/2001/XMLSchema-instance"
+
+// MarshalXML implements [xml.Marshaler]. [Some] value is encoded as the element,
+// [None] value omits the element, as omitempty does for empty values.
+//
+// [None] isn't presented as an empty element (e.g. <name/>): [xml.Encoder] can't write
+// self-closing tags, and an empty element is the valid presentation of Some("") for
+// Optional[string], so [None] couldn't survive a round-trip. Omitting the element keeps
+// the round-trip exact: an absent element is decoded as [None] by [Optional.UnmarshalXML].
+func (opt Optional[T]) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	if !opt.some {
+		return nil
+	}
+
+	return e.EncodeElement(opt.v, start)
+}
+
+// UnmarshalXML implements [xml.Unmarshaler]. A present element is decoded as [Some] value,
+// including an empty element, e.g. <name/> is decoded as Some("") for Optional[string].
+// An element with xsi:nil="true" attribute is decoded as [None].
+// An absent element leaves the [Optional] unchanged, i.e. [None] for a new value.
+// In case of error the [Optional] is reset to [None] and the error is returned.
+func (opt *Optional[T]) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	for _, attr := range start.Attr {
+		if attr.Name.Space == xsiNamespace && attr.Name.Local == "nil" && attr.Value == "true" {
+			*opt = None[T]()
+			return d.Skip()
+		}
+	}
+
+	var v T
+	if err := d.DecodeElement(&v, &start); err != nil {
+		*opt = None[T]()
+		return err
+	}
+
+	*opt = Some(v)
+	return nil
+}
+
+// MarshalXMLAttr implements [xml.MarshalerAttr]. [Some] value is presented as the attribute,
+// [None] value omits the attribute. The value is formatted with MarshalText method
+// if T implements [encoding.TextMarshaler], otherwise with [fmt.Sprint]. Structs, maps, slices,
+// arrays and pointers to structs can't be presented as text and are reported as an error.
+func (opt Optional[T]) MarshalXMLAttr(name xml.Name) (xml.Attr, error) {
+	if !opt.some {
+		return xml.Attr{}, nil
+	}
+
+	s, err := marshalText(opt.v)
+	if err != nil {
+		return xml.Attr{}, err
+	}
+
+	return xml.Attr{Name: name, Value: s}, nil
+}
+
+// UnmarshalXMLAttr implements [xml.UnmarshalerAttr]. A present attribute is decoded as [Some] value,
+// an absent attribute leaves the [Optional] unchanged, i.e. [None] for a new value.
+// The parsing rules are the same as for [Optional.ScanText].
+func (opt *Optional[T]) UnmarshalXMLAttr(attr xml.Attr) error {
+	return opt.parseText(attr.Value)
+}
+
+// marshalText formats v as text, values which can't be parsed back from the text
+// (structs, maps, slices, arrays and pointers to structs) are reported as an error.
+func marshalText(v any) (string, error) {
+	switch v := v.(type) {
+	case encoding.TextMarshaler:
+		b, err := v.MarshalText()
+		return string(b), err
+	case []byte:
+		return string(v), nil
+	}
+
+	rv := reflect.ValueOf(v)
+	if rv.Kind() == reflect.Pointer && rv.Type().Elem().Kind() == reflect.Struct {
+		return "", fmt.Errorf("box: can't marshal %T as text", v)
+	}
+	switch rv.Kind() {
+	case reflect.Struct, reflect.Map, reflect.Slice, reflect.Array:
+		return "", fmt.Errorf("box: can't marshal %T as text", v)
+	}
+
+	return fmt.Sprint(v), nil
+}
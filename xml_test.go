@@ -0,0 +1,180 @@
+package box
+
+import (
+	"encoding/xml"
+	"testing"
+)
+
+func TestOptional_MarshalXMLAttr(t *testing.T) {
+	type Item struct {
+		XMLName xml.Name          `xml:"item"`
+		ID      Optional[int]     `xml:"id,attr"`
+		Color   Optional[string]  `xml:"color,attr"`
+		Weight  Optional[float64] `xml:"weight,attr"`
+	}
+
+	b, err := xml.Marshal(Item{ID: Some(7), Weight: Some(1.5)})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := `<item id="7" weight="1.5"></item>`
+	if string(b) != want {
+		t.Errorf("got %s, want %s", b, want)
+	}
+
+	b, err = xml.Marshal(Item{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `<item></item>`; string(b) != want {
+		t.Errorf("got %s, want %s", b, want)
+	}
+}
+
+func TestOptional_UnmarshalXMLAttr(t *testing.T) {
+	type Item struct {
+		ID    Optional[int]    `xml:"id,attr"`
+		Color Optional[string] `xml:"color,attr"`
+	}
+
+	var item Item
+	if err := xml.Unmarshal([]byte(`<item id="7"></item>`), &item); err != nil {
+		t.Fatal(err)
+	}
+	if want := (Item{ID: Some(7)}); item != want {
+		t.Errorf("got %+v, want %+v", item, want)
+	}
+
+	item = Item{}
+	if err := xml.Unmarshal([]byte(`<item color=""></item>`), &item); err != nil {
+		t.Fatal(err)
+	}
+	if want := (Item{Color: Some("")}); item != want {
+		t.Errorf("got %+v, want %+v", item, want)
+	}
+
+	if err := xml.Unmarshal([]byte(`<item id="x"></item>`), &item); err == nil {
+		t.Error("expected error on invalid attribute value")
+	}
+
+	b, _ := xml.Marshal(Item{ID: Some(1), Color: Some("red")})
+	var decoded Item
+	if err := xml.Unmarshal(b, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if want := (Item{ID: Some(1), Color: Some("red")}); decoded != want {
+		t.Errorf("round-trip: got %+v, want %+v", decoded, want)
+	}
+}
+
+func TestOptional_MarshalXML(t *testing.T) {
+	type Address struct {
+		City Optional[string] `xml:"city"`
+		Zip  Optional[int]    `xml:"zip"`
+	}
+	type Person struct {
+		XMLName xml.Name          `xml:"person"`
+		Name    Optional[string]  `xml:"name"`
+		Address Optional[Address] `xml:"address"`
+	}
+
+	tests := []struct {
+		name string
+		in   Person
+		want string
+	}{
+		{"populated", Person{Name: Some("Bob"), Address: Some(Address{City: Some("Oslo"), Zip: Some(150)})},
+			`<person><name>Bob</name><address><city>Oslo</city><zip>150</zip></address></person>`},
+		{"nested none", Person{Name: Some(""), Address: Some(Address{City: Some("Oslo")})},
+			`<person><name></name><address><city>Oslo</city></address></person>`},
+		{"none", Person{}, `<person></person>`},
+	}
+
+	for _, tt := range tests {
+		b, err := xml.Marshal(tt.in)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if string(b) != tt.want {
+			t.Errorf("%s: got %s, want %s", tt.name, b, tt.want)
+		}
+
+		var out Person
+		if err := xml.Unmarshal(b, &out); err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		out.XMLName = xml.Name{}
+		if out != tt.in {
+			t.Errorf("%s: round-trip got %+v, want %+v", tt.name, out, tt.in)
+		}
+	}
+}
+
+func TestOptional_UnmarshalXML(t *testing.T) {
+	type Item struct {
+		Name  Optional[string] `xml:"name"`
+		Count Optional[int]    `xml:"count"`
+	}
+
+	tests := []struct {
+		name    string
+		in      string
+		init    Item
+		want    Item
+		wantErr bool
+	}{
+		{"self-closing", `<item><name/></item>`, Item{}, Item{Name: Some("")}, false},
+		{"xsi:nil", `<item xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance"><name xsi:nil="true"/><count>2</count></item>`,
+			Item{Name: Some("old")}, Item{Count: Some(2)}, false},
+		{"absent", `<item></item>`, Item{Name: Some("old")}, Item{Name: Some("old")}, false},
+		{"invalid", `<item><count>x</count></item>`, Item{Count: Some(1)}, Item{}, true},
+	}
+
+	for _, tt := range tests {
+		item := tt.init
+		err := xml.Unmarshal([]byte(tt.in), &item)
+		if (err != nil) != tt.wantErr {
+			t.Fatalf("%s: error = %v, wantErr %v", tt.name, err, tt.wantErr)
+		}
+		if item != tt.want {
+			t.Errorf("%s: got %+v, want %+v", tt.name, item, tt.want)
+		}
+	}
+}
+
+func TestOptional_XML_roundTrip(t *testing.T) {
+	type Item struct {
+		XMLName xml.Name         `xml:"item"`
+		Name    Optional[string] `xml:"name"`
+		Count   Optional[int]    `xml:"count"`
+	}
+
+	tests := []struct {
+		in   Item
+		want string
+	}{
+		{Item{}, `<item></item>`},
+		{Item{Name: Some(""), Count: Some(0)}, `<item><name></name><count>0</count></item>`},
+		{Item{Name: Some("a")}, `<item><name>a</name></item>`},
+	}
+
+	for _, tt := range tests {
+		b, err := xml.Marshal(tt.in)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(b) != tt.want {
+			t.Errorf("%+v: got %s, want %s", tt.in, b, tt.want)
+		}
+
+		var out Item
+		if err := xml.Unmarshal(b, &out); err != nil {
+			t.Fatal(err)
+		}
+		out.XMLName = xml.Name{}
+		if out != tt.in {
+			t.Errorf("%s: round-trip got %+v, want %+v", b, out, tt.in)
+		}
+	}
+}